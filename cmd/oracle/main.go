@@ -58,11 +58,16 @@ The mode argument determines the query to perform:
 	callers	  	show possible callers of selected function
 	callgraph 	show complete callgraph of program
 	callstack 	show path from callgraph root to selected function
+	convert=T 	show whether selected expression is assignable or convertible to type T
 	describe  	describe selected syntax: definition, methods, etc
 	freevars  	show free variables of selection
+	goroutines	show go statements reachable from selected function
 	implements	show 'implements' relation for selected package
+	mapkey    	show map types keyed by selected type
 	peers     	show send/receive corresponding to selected channel op
+	recursion 	show whether selected function may call itself
 	referrers 	show all refs to entity denoted by selected identifier
+	ssa       	show SSA block computing selected expression
 
 The user manual is available here:  http://golang.org/s/oracle-user-manual
 
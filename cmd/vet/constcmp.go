@@ -0,0 +1,79 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+/*
+This file contains the code to check for comparisons of constants
+within && and || chains. Such a comparison is folded by the type
+checker and is always true or always false, which usually means the
+chain was meant to mention a variable.
+
+Comparisons involving constants from other packages (runtime.GOOS,
+strconv.IntSize and the like) are configuration tests and are not
+reported. The check is experimental and must be enabled explicitly.
+*/
+
+package main
+
+import (
+	"go/ast"
+	"go/token"
+
+	"code.google.com/p/go.tools/go/exact"
+	"code.google.com/p/go.tools/go/types"
+)
+
+func init() {
+	register("constcmp",
+		"check for constant comparisons in && and || chains (experimental; must be set explicitly)",
+		checkConstCmp,
+		binaryExpr)
+	experimental["constcmp"] = true
+}
+
+// checkConstCmp reports each comparison operand of an && or || expression
+// whose value is a constant. Since every comparison is an operand of at
+// most one such expression, each is reported once.
+func checkConstCmp(f *File, node ast.Node) {
+	e := node.(*ast.BinaryExpr)
+	if e.Op != token.LAND && e.Op != token.LOR {
+		return
+	}
+	for _, x := range []ast.Expr{e.X, e.Y} {
+		c, ok := unparen(x).(*ast.BinaryExpr)
+		if !ok || !isComparison(c.Op) {
+			continue
+		}
+		v := f.pkg.types[c].Value
+		if v == nil || v.Kind() != exact.Bool || f.usesImportedConst(c) {
+			continue
+		}
+		f.Badf(c.Pos(), "comparison %s is always %v", f.gofmt(c), exact.BoolVal(v))
+	}
+}
+
+// isComparison reports whether op is a comparison operator.
+func isComparison(op token.Token) bool {
+	switch op {
+	case token.EQL, token.NEQ, token.LSS, token.LEQ, token.GTR, token.GEQ:
+		return true
+	}
+	return false
+}
+
+// usesImportedConst reports whether e refers to a qualified identifier
+// from an imported package.
+func (f *File) usesImportedConst(e ast.Expr) bool {
+	found := false
+	ast.Inspect(e, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok {
+				if _, ok := f.pkg.uses[id].(*types.PkgName); ok {
+					found = true
+				}
+			}
+		}
+		return !found
+	})
+	return found
+}
@@ -141,6 +141,14 @@ Flag: -shadow=false (experimental; must be set explicitly)
 
 Variables that may have been unintentionally shadowed.
 
+Constant comparisons
+
+Flag: -constcmp=false (experimental; must be set explicitly)
+
+Comparisons of constants within && and || chains, which are always
+true or always false. Comparisons of constants from imported packages,
+such as runtime.GOOS, are not reported.
+
 Misuse of unsafe Pointers
 
 Flag: -unsafeptr
@@ -177,6 +185,6 @@ These flags configure the behavior of vet:
 	-shadowstrict
 		Whether to be strict about shadowing; can be noisy.
 	-test
-		For testing only: sets -all and -shadow.
+		For testing only: sets -all, -shadow and -constcmp.
 */
 package main
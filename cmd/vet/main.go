@@ -28,7 +28,7 @@ import (
 // TODO: Need a flag to set build tags when parsing the package.
 
 var verbose = flag.Bool("v", false, "verbose")
-var testFlag = flag.Bool("test", false, "for testing only: sets -all and -shadow")
+var testFlag = flag.Bool("test", false, "for testing only: sets -all, -shadow and -constcmp")
 var exitCode = 0
 
 // "all" is here only for the appearance of backwards compatibility.
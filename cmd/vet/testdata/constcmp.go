@@ -0,0 +1,36 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// This file contains tests for the constcmp checker.
+
+package testdata
+
+import "runtime"
+
+const (
+	lo  = 1
+	mid = 5
+	hi  = 10
+)
+
+func ConstCmpTests(x int) {
+	// Chains consisting solely of constant comparisons.
+	_ = lo < mid && mid < hi               // ERROR "comparison lo < mid is always true" "comparison mid < hi is always true"
+	_ = hi < mid || mid < lo               // ERROR "comparison hi < mid is always false" "comparison mid < lo is always false"
+	_ = lo <= mid && mid <= hi && hi != lo // ERROR "comparison lo <= mid is always true" "comparison mid <= hi is always true" "comparison hi != lo is always true"
+
+	// Mixed chains: only the constant comparisons are reported.
+	_ = lo < x && x < hi
+	_ = x > lo && mid < hi   // ERROR "comparison mid < hi is always true"
+	_ = (hi == lo) || x == 0 // ERROR "comparison hi == lo is always false"
+	_ = x == mid || x == hi
+
+	// Comparisons outside a chain are not reported.
+	_ = lo < hi
+	if mid == 5 {
+	}
+
+	// Configuration tests using imported constants are not reported.
+	_ = runtime.GOOS == "windows" && x > 0
+}
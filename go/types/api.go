@@ -112,7 +112,31 @@ type Config struct {
 
 	// If Sizes != nil, it provides the sizing functions for package unsafe.
 	// Otherwise &StdSizes{WordSize: 8, MaxAlign: 8} is used instead.
+	// The sizes of int, uint and uintptr also determine which constant
+	// values are representable in those types, so Sizes must match the
+	// target platform, e.g. &StdSizes{WordSize: 4, MaxAlign: 4} for 386.
 	Sizes Sizes
+
+	// If MaxShift > 0, it is the largest permitted constant shift
+	// count. Otherwise defaultMaxShift is used.
+	MaxShift int
+
+	// If MaxConstBits > 0, it limits the number of bits needed to
+	// represent an untyped constant value (or, for non-integer values,
+	// its numerator and denominator). Otherwise defaultMaxConstBits
+	// is used. Constant operations exceeding the limit are reported
+	// as errors rather than computed with ever-growing precision.
+	MaxConstBits int
+
+	// If PortabilityMode is set, constants must be representable in
+	// int, uint, and uintptr on all platforms: values of these types
+	// are limited to 32 bits even if Sizes specifies a larger word
+	// size. This catches code such as `const x int = 1 << 40` that
+	// only compiles for 64-bit platforms.
+	// Constant values are still computed for the word size specified
+	// by Sizes; those that depend on it, such as ^uint(0) on a 64-bit
+	// platform, are reported as errors.
+	PortabilityMode bool
 }
 
 // DefaultImport is the default importer invoked if Config.Import == nil.
@@ -137,6 +161,12 @@ type Info struct {
 	// an argument-specific signature. Otherwise, the recorded type
 	// is invalid.
 	//
+	// For composite literals, the recorded type is the literal's type
+	// after inference: for [...]T array literals it is the array type
+	// with the actual length, and for inner literals whose type is
+	// elided (as in []T{{...}} or []*T{{...}}) it is the type supplied
+	// by the enclosing literal (T, or *T for an elided &T).
+	//
 	// Identifiers on the lhs of declarations (i.e., the identifiers
 	// which are being declared) are collected in the Defs map.
 	// Identifiers denoting packages are collected in the Uses maps.
@@ -341,6 +371,10 @@ func AssertableTo(V *Interface, T Type) bool {
 }
 
 // AssignableTo reports whether a value of type V is assignable to a variable of type T.
+// If V is an untyped type, AssignableTo reports whether some value of
+// that type is assignable to T; e.g. untyped nil is assignable to
+// pointer types, and untyped numeric values to numeric types. Whether
+// a particular constant value is representable in T is not checked.
 func AssignableTo(V, T Type) bool {
 	x := operand{mode: value, typ: V}
 	return x.assignableTo(nil, T) // config not needed for non-constant x
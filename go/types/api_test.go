@@ -111,6 +111,73 @@ func TestValuesInfo(t *testing.T) {
 		{`package f7a; var _ complex128 = -1e-2000i`, `-1e-2000i`, `complex128`, `0`},
 		{`package f6b; var _            =  1e-2000i`, `1e-2000i`, `complex128`, `0`},
 		{`package f7b; var _            = -1e-2000i`, `-1e-2000i`, `complex128`, `0`},
+
+		{`package g0; const _ = 1 < 2`, `1 < 2`, `untyped bool`, `true`},
+		{`package g1; var _ = 1 < 2`, `1 < 2`, `bool`, `true`},
+		{`package g2; var _ bool = 1 < 2`, `1 < 2`, `bool`, `true`},
+		{`package g3; type T bool; var _ T = 1 > 2`, `1 > 2`, `g3.T`, `false`},
+		{`package g4; var _ bool = (1 < 2) == (3 < 4)`, `(1 < 2) == (3 < 4)`, `bool`, `true`},
+
+		// integer constants rounded to a (named) floating-point type
+		{`package h0; const _ = float32(16777217)`, `float32(16777217)`, `float32`, `16777216`},
+		{`package h1; type T float32; const _ = T(16777217)`, `T(16777217)`, `h1.T`, `16777216`},
+		{`package h2; type T float32; var _ T = 16777217`, `16777217`, `h2.T`, `16777216`},
+		{`package h3; const _ float64 = 9007199254740993`, `9007199254740993`, `float64`, `9007199254740992`},
+		{`package h4; const _ complex64 = 16777217`, `16777217`, `complex64`, `16777216`},
+
+		// bitwise complement of typed constants
+		{`package l0; const _ = ^uint8(0)`, `^uint8(0)`, `uint8`, `255`},
+		{`package l1; const _ = ^int8(0)`, `^int8(0)`, `int8`, `-1`},
+		{`package l2; const _ = ^int8(-128)`, `^int8(-128)`, `int8`, `127`},
+		{`package l3; const _ = ^uint16(1)`, `^uint16(1)`, `uint16`, `65534`},
+		{`package l4; const _ = ^uint64(0)`, `^uint64(0)`, `uint64`, `18446744073709551615`},
+		{`package l5; type T uint8; const _ = ^T(0xf0)`, `^T(0xf0)`, `l5.T`, `15`},
+
+		// untyped boolean constants assigned to named boolean types
+		{`package m0; type B bool; var _ B = true`, `true`, `m0.B`, `true`},
+		{`package m1; type B bool; var _ B = false`, `false`, `m1.B`, `false`},
+		{`package m2; type B bool; const _ B = 1 < 2`, `1 < 2`, `m2.B`, `true`},
+		{`package m3; type B bool; type C B; var _ C = !true`, `!true`, `m3.C`, `false`},
+
+		// constant values and types propagate through nested parentheses
+		{`package n0; const _ = ((1 + 2))`, `((1 + 2))`, `untyped int`, `3`},
+		{`package n1; const _ = ((1 + 2))`, `(1 + 2)`, `untyped int`, `3`},
+		{`package n2; const _ = ((1 + 2))`, `1 + 2`, `untyped int`, `3`},
+		{`package n3; var _ int64 = ((1 + 2))`, `((1 + 2))`, `int64`, `3`},
+		{`package n4; var _ int64 = ((1 + 2))`, `(1 + 2)`, `int64`, `3`},
+		{`package n5; var _ int64 = ((1 + 2))`, `1 + 2`, `int64`, `3`},
+		{`package n6; var _ float32 = (((1)) << ((2)))`, `(((1)) << ((2)))`, `float32`, `4`},
+		{`package n7; var _ = ((('a')))`, `((('a')))`, `rune`, `97`},
+		{`package n8; const _ = ((1.5)) * (((2)))`, `(((2)))`, `untyped float`, `2`},
+
+		// untyped constants converted to named types record the named type
+		{`package o0; type C float64; var _ C = 100`, `100`, `o0.C`, `100`},
+		{`package o1; type C float64; const _ C = 1.5`, `1.5`, `o1.C`, `3/2`},
+		{`package o2; type S int8; var _ S = -1 << 7`, `-1 << 7`, `o2.S`, `-128`},
+		{`package o3; type S int8; func f(S) {}; func _() { f('a') }`, `'a'`, `o3.S`, `97`},
+		{`package o4; type L string; var _ = L("foo") + "bar"`, `"bar"`, `o4.L`, `"bar"`},
+
+		// real constants converted to complex types
+		{`package k0; const _ = complex128(3)`, `complex128(3)`, `complex128`, `3`},
+		{`package k1; const _ = complex64(1.5)`, `complex64(1.5)`, `complex64`, `3/2`},
+		{`package k2; type C complex64; const _ = C(16777217)`, `C(16777217)`, `k2.C`, `16777216`},
+		{`package k3; const _ = complex128(-1e-2000)`, `complex128(-1e-2000)`, `complex128`, `0`},
+
+		// unary + leaves the value and type of a numeric operand unchanged
+		{`package j0; const _ = +42`, `+42`, `untyped int`, `42`},
+		{`package j1; const _ = +'a'`, `+'a'`, `untyped rune`, `97`},
+		{`package j2; const _ = +(1 << 70)`, `+(1 << 70)`, `untyped int`, `1180591620717411303424`},
+		{`package j3; type T int8; const _ = +T(-128)`, `+T(-128)`, `j3.T`, `-128`},
+		{`package j4; const _ = +1.5`, `+1.5`, `untyped float`, `3/2`},
+		{`package j5; var _ float32 = +1.5`, `+1.5`, `float32`, `3/2`},
+		{`package j6; const _ = +2i`, `+2i`, `untyped complex`, `(0/1 + 2/1i)`},
+		{`package j7; var x uint; var _ = +x`, `+x`, `uint`, ``},
+
+		// conversions of constant strings to byte slices are not constant
+		{`package i0; var _ = []byte("abc")`, `[]byte("abc")`, `[]byte`, ``},
+		{`package i1; const s = "abc"; var _ = []byte(s)`, `[]byte(s)`, `[]byte`, ``},
+		{`package i2; type B []byte; var _ = B("abc")`, `B("abc")`, `i2.B`, ``},
+		{`package i3; var _ = []byte("abc")`, `"abc"`, `string`, `"abc"`},
 	}
 
 	for _, test := range tests {
@@ -139,8 +206,12 @@ func TestValuesInfo(t *testing.T) {
 			continue
 		}
 
-		// check that value is correct
-		if got := tv.Value.String(); got != test.val {
+		// check that value is correct (an empty val means not constant)
+		var got string
+		if tv.Value != nil {
+			got = tv.Value.String()
+		}
+		if got != test.val {
 			t.Errorf("package %s: got value %s; want %s", name, got, test.val)
 		}
 	}
@@ -182,6 +253,71 @@ func TestTypesInfo(t *testing.T) {
 			`<-c`,
 			`(string, bool)`,
 		},
+		{`package p4; var c chan string; func _() { v, ok := <-c; _, _ = v, ok }`,
+			`<-c`,
+			`(string, bool)`,
+		},
+		{`package p5; var c chan string; func _() { v := <-c; _ = v }`,
+			`<-c`,
+			`string`,
+		},
+
+		// comma-ok type assertions in if statement initializers
+		{`package p6; type T struct{}; var x interface{}; func _() { if v, ok := x.(*T); ok { _ = v } }`,
+			`x.(*T)`,
+			`(*p6.T, bool)`,
+		},
+		{`package p7; type T struct{}; var x interface{}; func _() { if v, ok := x.(*T); ok { _ = v } }`,
+			`v`,
+			`*p7.T`,
+		},
+		{`package p8; type T struct{}; var x interface{}; func _() { if v, ok := x.(*T); ok { _ = v } }`,
+			`ok`,
+			`bool`,
+		},
+		{`package p9; type I interface{ m() }; var x interface{}; func _() { if v, _ := x.(I); v != nil { v.m() } }`,
+			`v.m`,
+			`func()`,
+		},
+
+		// elided composite literals of pointer element type
+		// (ExprString prints the missing literal type as "(bad expr)")
+		{`package q0; type Point struct{ x, y int }; var _ = []*Point{{1, 2}}`,
+			`((bad expr) literal)`,
+			`*q0.Point`,
+		},
+		{`package q1; type T struct{ s string }; var _ = map[string]*T{"a": {s: "x"}}`,
+			`((bad expr) literal)`,
+			`*q1.T`,
+		},
+		{`package q2; var _ = [...]*[]int{{1, 2}}`,
+			`((bad expr) literal)`,
+			`*[]int`,
+		},
+
+		// untyped delete keys
+		{`package d0; var m map[int8]bool; func _() { delete(m, 1) }`,
+			`1`,
+			`int8`,
+		},
+		{`package d1; var m map[interface{}]bool; func _() { delete(m, 1.0) }`,
+			`1.0`,
+			`float64`,
+		},
+
+		// conversions in binary expressions
+		{`package p6; var x int32; var y int64; var _ = int64(x) + y`,
+			`int64(x) + y`,
+			`int64`,
+		},
+		{`package p7; type T float64; var x int; var _ = T(x) * 2.5`,
+			`T(x) * 2.5`,
+			`p7.T`,
+		},
+		{`package p8; var x int32; var _ = -int64(x) == 0`,
+			`-int64(x)`,
+			`int64`,
+		},
 
 		// issue 6796
 		{`package issue6796_a; var x interface{}; var _, _ = (x.(int))`,
@@ -230,6 +366,40 @@ func TestTypesInfo(t *testing.T) {
 			`<-ch`,
 			`(string, bool)`,
 		},
+
+		// untyped bool results of non-constant comparisons
+		{`package cmp0; var x int; var b bool = x < 2`,
+			`x < 2`,
+			`bool`,
+		},
+		{`package cmp1; type T bool; var x int; var b T = x < 2`,
+			`x < 2`,
+			`cmp1.T`,
+		},
+		{`package cmp2; var x int; var b = x < 2`,
+			`x < 2`,
+			`bool`,
+		},
+
+		// chained method calls
+		{`package chain_a; type A int; type B string; type C []A; func (A) B() B; func (B) C() C; func (C) D() bool; var x A; var _ = x.B().C().D()`,
+			`x.B()`,
+			`chain_a.B`,
+		},
+		{`package chain_b; type A int; type B string; type C []A; func (A) B() B; func (B) C() C; func (C) D() bool; var x A; var _ = x.B().C().D()`,
+			`x.B().C()`,
+			`chain_b.C`,
+		},
+		{`package chain_c; type A int; type B string; type C []A; func (A) B() B; func (B) C() C; func (C) D() bool; var x A; var _ = x.B().C().D()`,
+			`x.B().C().D()`,
+			`bool`,
+		},
+
+		// untyped nil assigned to a named unsafe.Pointer
+		{`package unsafe_nil; import "unsafe"; type P unsafe.Pointer; var _ P = nil`,
+			`nil`,
+			`unsafe_nil.P`,
+		},
 	}
 
 	for _, test := range tests {
@@ -901,3 +1071,402 @@ func sameSlice(a, b []int) bool {
 	}
 	return true
 }
+
+func TestMaxConstBits(t *testing.T) {
+	const src = `
+package p
+const (
+	a = 1 << 60
+	b = a * 8
+	c = 1.0 / b
+	d = b * b
+)
+`
+	f, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var errs []string
+	conf := Config{
+		MaxConstBits: 64,
+		Error:        func(err error) { errs = append(errs, err.Error()) },
+	}
+	conf.Check(f.Name.Name, fset, []*ast.File{f}, nil) // errors collected via conf.Error
+
+	if len(errs) != 1 || !strings.HasSuffix(errs[0], "constant too large") || !strings.HasPrefix(errs[0], "p.go:7:") {
+		t.Errorf("got errors %q, want a single \"constant too large\" error at p.go:7", errs)
+	}
+}
+
+func TestMaxShift(t *testing.T) {
+	const src = `
+package p
+const (
+	a = 1 << 200
+	b = 1 << 201
+	c = 1 << 2000
+)
+`
+	f, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, test := range []struct {
+		max  int
+		want []string
+	}{
+		{0, []string{"p.go:6:11: invalid operation: stupid shift count 2000 (untyped int constant) (limit 1074)"}},
+		{200, []string{
+			"p.go:5:11: invalid operation: stupid shift count 201 (untyped int constant) (limit 200)",
+			"p.go:6:11: invalid operation: stupid shift count 2000 (untyped int constant) (limit 200)",
+		}},
+		{2000, nil},
+	} {
+		var errs []string
+		conf := Config{
+			MaxShift: test.max,
+			Error:    func(err error) { errs = append(errs, err.Error()) },
+		}
+		conf.Check(f.Name.Name, fset, []*ast.File{f}, nil) // errors collected via conf.Error
+
+		if fmt.Sprint(errs) != fmt.Sprint(test.want) {
+			t.Errorf("MaxShift = %d: got errors %q, want %q", test.max, errs, test.want)
+		}
+	}
+}
+
+func TestAssignableTo(t *testing.T) {
+	intPtr := NewPointer(Typ[Int])
+	myInt := NewNamed(NewTypeName(token.NoPos, nil, "myInt", nil), Typ[Int], nil)
+	empty := NewInterface(nil, nil).Complete()
+	stringer := NewInterface([]*Func{
+		NewFunc(token.NoPos, nil, "String", NewSignature(nil, nil, nil, NewTuple(NewVar(token.NoPos, nil, "", Typ[String])), false)),
+	}, nil).Complete()
+
+	for _, test := range []struct {
+		V, T Type
+		want bool
+	}{
+		{Typ[Int], Typ[Int], true},
+		{Typ[Int], Typ[Int64], false},
+		{Typ[Int], myInt, false},
+		{myInt, Typ[Int], false},
+		{Typ[Int], empty, true},
+		{Typ[Int], stringer, false},
+		{intPtr, NewPointer(Typ[Int]), true},
+		{NewSlice(Typ[Int]), NewSlice(Typ[Int]), true},
+
+		// untyped nil
+		{Typ[UntypedNil], intPtr, true},
+		{Typ[UntypedNil], NewSlice(Typ[Int]), true},
+		{Typ[UntypedNil], empty, true},
+		{Typ[UntypedNil], stringer, true},
+		{Typ[UntypedNil], Typ[UnsafePointer], true},
+		{Typ[UntypedNil], Typ[Int], false},
+		{Typ[UntypedNil], Typ[String], false},
+
+		// other untyped values
+		{Typ[UntypedBool], Typ[Bool], true},
+		{Typ[UntypedBool], Typ[Int], false},
+		{Typ[UntypedInt], Typ[Int8], true},
+		{Typ[UntypedInt], Typ[Float64], true},
+		{Typ[UntypedInt], myInt, true},
+		{Typ[UntypedRune], Typ[Int32], true},
+		{Typ[UntypedFloat], Typ[Complex64], true},
+		{Typ[UntypedFloat], Typ[String], false},
+		{Typ[UntypedString], Typ[String], true},
+		{Typ[UntypedString], Typ[Int], false},
+		{Typ[UntypedInt], empty, true},
+		{Typ[UntypedInt], stringer, false},
+		{Typ[UntypedInt], intPtr, false},
+	} {
+		if got := AssignableTo(test.V, test.T); got != test.want {
+			t.Errorf("AssignableTo(%s, %s) = %t, want %t", test.V, test.T, got, test.want)
+		}
+	}
+}
+
+func TestComparableOrdered(t *testing.T) {
+	myInt := NewNamed(NewTypeName(token.NoPos, nil, "myInt", nil), Typ[Int], nil)
+	fn := NewSignature(nil, nil, nil, nil, false)
+	field := func(name string, typ Type) *Var { return NewField(token.NoPos, nil, name, typ, false) }
+
+	for _, test := range []struct {
+		T                   Type
+		comparable, ordered bool
+	}{
+		{Typ[Bool], true, false},
+		{Typ[Int], true, true},
+		{Typ[Float64], true, true},
+		{Typ[Complex128], true, false},
+		{Typ[String], true, true},
+		{Typ[UnsafePointer], true, false},
+		{myInt, true, true},
+		{NewPointer(Typ[Int]), true, false},
+		{NewChan(SendRecv, Typ[Int]), true, false},
+		{NewInterface(nil, nil).Complete(), true, false},
+		{NewStruct(nil, nil), true, false},
+		{NewStruct([]*Var{field("x", Typ[Int]), field("s", Typ[String])}, nil), true, false},
+		{NewStruct([]*Var{field("x", Typ[Int]), field("f", fn)}, nil), false, false},
+		{NewArray(Typ[Int], 4), true, false},
+		{NewArray(fn, 4), false, false},
+		{NewArray(NewSlice(Typ[Int]), 0), false, false},
+		{NewNamed(NewTypeName(token.NoPos, nil, "F", nil), fn, nil), false, false},
+		{NewSlice(Typ[Int]), false, false},
+		{NewMap(Typ[String], Typ[Int]), false, false},
+		{fn, false, false},
+	} {
+		if got := Comparable(test.T); got != test.comparable {
+			t.Errorf("Comparable(%s) = %t, want %t", test.T, got, test.comparable)
+		}
+		if got := Ordered(test.T); got != test.ordered {
+			t.Errorf("Ordered(%s) = %t, want %t", test.T, got, test.ordered)
+		}
+	}
+}
+
+func TestCompositeLitTypes(t *testing.T) {
+	const src = `
+package p
+
+type P struct{ x, y int }
+
+var (
+	_ = [...]P{{1, 2}, {3, 4}, 5: {}}
+	_ = []*P{{1, 2}}
+	_ = map[string][][]P{"a": {{{}}}}
+)
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "CompositeLitTypes", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := Info{Types: make(map[ast.Expr]TypeAndValue)}
+	var conf Config
+	if _, err := conf.Check(f.Name.Name, fset, []*ast.File{f}, &info); err != nil {
+		t.Fatal(err)
+	}
+
+	// collect literal types in source order
+	var got []string
+	ast.Inspect(f, func(n ast.Node) bool {
+		if lit, _ := n.(*ast.CompositeLit); lit != nil {
+			got = append(got, info.Types[lit].Type.String())
+		}
+		return true
+	})
+
+	want := []string{
+		"[6]p.P", "p.P", "p.P", "p.P",
+		"[]*p.P", "*p.P",
+		"map[string][][]p.P", "[][]p.P", "[]p.P", "p.P",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got composite literal types %v, want %v", got, want)
+	}
+}
+
+// TestCompositeLitElementTypes shows how clients can relate each
+// element of a composite literal to the element type it is assigned
+// to, using only the types recorded in Info.Types.
+func TestCompositeLitElementTypes(t *testing.T) {
+	const src = `
+package p
+
+type T int
+
+var x T
+
+var (
+	_ = []interface{}{1, 2.5, "a", x}
+	_ = []float64{1, 2: 'a'}
+	_ = map[string]interface{}{"k": x}
+)
+`
+	fset := token.NewFileSet()
+	f, err := parser.ParseFile(fset, "CompositeLitElementTypes", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info := Info{Types: make(map[ast.Expr]TypeAndValue)}
+	var conf Config
+	if _, err := conf.Check(f.Name.Name, fset, []*ast.File{f}, &info); err != nil {
+		t.Fatal(err)
+	}
+
+	// collect (element type, element's own type) pairs in source order
+	var got []string
+	ast.Inspect(f, func(n ast.Node) bool {
+		lit, _ := n.(*ast.CompositeLit)
+		if lit == nil {
+			return true
+		}
+		var elem Type
+		switch typ := info.Types[lit].Type.Underlying().(type) {
+		case *Slice:
+			elem = typ.Elem()
+		case *Map:
+			elem = typ.Elem()
+		}
+		for _, e := range lit.Elts {
+			if kv, _ := e.(*ast.KeyValueExpr); kv != nil {
+				e = kv.Value
+			}
+			got = append(got, fmt.Sprintf("%s=%s", elem, info.Types[e].Type))
+		}
+		return true
+	})
+
+	want := []string{
+		"interface{}=int", "interface{}=float64", "interface{}=string", "interface{}=p.T",
+		"float64=float64", "float64=float64",
+		"interface{}=p.T",
+	}
+	if fmt.Sprint(got) != fmt.Sprint(want) {
+		t.Errorf("got element types %v, want %v", got, want)
+	}
+}
+
+func TestDefaultType(t *testing.T) {
+	myInt := NewNamed(NewTypeName(token.NoPos, nil, "myInt", nil), Typ[Int], nil)
+
+	for _, test := range []struct {
+		T, want Type
+	}{
+		{Typ[UntypedBool], Typ[Bool]},
+		{Typ[UntypedInt], Typ[Int]},
+		{Typ[UntypedRune], UniverseRune},
+		{Typ[UntypedFloat], Typ[Float64]},
+		{Typ[UntypedComplex], Typ[Complex128]},
+		{Typ[UntypedString], Typ[String]},
+		{Typ[UntypedNil], Typ[UntypedNil]}, // untyped nil has no default type
+		{Typ[Int8], Typ[Int8]},
+		{Typ[Float32], Typ[Float32]},
+		{UniverseByte, UniverseByte},
+		{myInt, myInt},
+		{NewSlice(Typ[UntypedInt]), NewSlice(Typ[UntypedInt])},
+	} {
+		if got := DefaultType(test.T); !Identical(got, test.want) {
+			t.Errorf("DefaultType(%s) = %s, want %s", test.T, got, test.want)
+		}
+	}
+
+	// the default type of an untyped rune is named rune, not int32
+	if got := DefaultType(Typ[UntypedRune]); got != UniverseRune {
+		t.Errorf("DefaultType(untyped rune) = %s, want rune", got)
+	}
+}
+
+func TestSizesRepresentability(t *testing.T) {
+	const src = `
+package p
+const (
+	a int = 1 << 40
+	b uint = 1 << 32
+	c uintptr = 1 << 32
+	d = ^uint(0)
+	e = uint(1) << 31 << 1
+	f int = 1 << 31 - 1
+)
+`
+	f, err := parser.ParseFile(fset, "p.go", src, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	errs32 := []string{
+		"p.go:4:10: 1 << 40 (untyped int constant 1099511627776) overflows int (valid range -2147483648..2147483647)",
+		"p.go:5:11: 1 << 32 (untyped int constant 4294967296) overflows uint (valid range 0..4294967295)",
+		"p.go:6:14: 1 << 32 (untyped int constant 4294967296) overflows uintptr (valid range 0..4294967295)",
+		"p.go:8:6: uint(1) << 31 (constant 4294967296 of type uint) overflows uint (valid range 0..4294967295)",
+	}
+
+	for _, test := range []struct {
+		wordSize int64
+		portable bool     // Config.PortabilityMode
+		errs     []string // expected errors, in order
+		d        string   // value of d
+	}{
+		{8, false, nil, "18446744073709551615"},
+		{4, false, errs32, "4294967295"},
+		// in portability mode, int, uint, and uintptr are limited to 32 bits,
+		// and constants are rejected rather than folded differently
+		{8, true, []string{
+			errs32[0],
+			errs32[1],
+			errs32[2],
+			"p.go:7:7: uint(0) (constant 18446744073709551615 of type uint) overflows uint (valid range 0..4294967295)",
+			errs32[3],
+		}, "unknown"},
+		{4, true, errs32, "4294967295"},
+	} {
+		var errs []string
+		conf := Config{
+			Sizes:           &StdSizes{WordSize: test.wordSize, MaxAlign: test.wordSize},
+			PortabilityMode: test.portable,
+			Error:           func(err error) { errs = append(errs, err.Error()) },
+		}
+		pkg, _ := conf.Check(f.Name.Name, fset, []*ast.File{f}, nil) // errors collected via conf.Error
+
+		if fmt.Sprint(errs) != fmt.Sprint(test.errs) {
+			t.Errorf("WordSize = %d, portable = %t: got errors %q, want %q", test.wordSize, test.portable, errs, test.errs)
+		}
+		if got := pkg.Scope().Lookup("d").(*Const).Val().String(); got != test.d {
+			t.Errorf("WordSize = %d, portable = %t: got d = %s, want %s", test.wordSize, test.portable, got, test.d)
+		}
+	}
+}
+
+func TestUnexportedStructLiteralFields(t *testing.T) {
+	fset := token.NewFileSet()
+	var errs []string
+	conf := Config{
+		Packages: make(map[string]*Package),
+		Error:    func(err error) { errs = append(errs, err.Error()) },
+		Import: func(imports map[string]*Package, path string) (*Package, error) {
+			return imports[path], nil
+		},
+	}
+	makePkg := func(path, src string) {
+		f, err := parser.ParseFile(fset, path, src, 0)
+		if err != nil {
+			t.Fatal(err)
+		}
+		pkg, _ := conf.Check(path, fset, []*ast.File{f}, nil) // errors collected via conf.Error
+		conf.Packages[path] = pkg
+	}
+
+	const libSrc = `
+package lib
+type T struct {
+	X, y int
+}
+var _ = T{X: 1, y: 2}
+`
+
+	const mainSrc = `
+package main
+import "lib"
+type U struct {
+	y int
+}
+var _ = lib.T{X: 1}
+var _ = lib.T{X: 1, y: 2}
+var _ = lib.T{z: 3}
+var _ = U{y: 4}
+`
+
+	makePkg("lib", libSrc)
+	makePkg("main", mainSrc)
+
+	want := []string{
+		"main:8:21: cannot refer to unexported field y in struct literal of package lib",
+		"main:9:15: unknown field z in struct literal",
+	}
+	if fmt.Sprint(errs) != fmt.Sprint(want) {
+		t.Errorf("got errors %q, want %q", errs, want)
+	}
+}
@@ -7,6 +7,7 @@
 package types
 
 import (
+	"fmt"
 	"go/ast"
 	"go/token"
 )
@@ -51,7 +52,7 @@ func (check *Checker) assignment(x *operand, T Type) bool {
 				x.mode = invalid
 				return false
 			}
-			target = defaultType(x.typ)
+			target = DefaultType(x.typ)
 		}
 		check.convertUntyped(x, target)
 		if x.mode == invalid {
@@ -65,6 +66,27 @@ func (check *Checker) assignment(x *operand, T Type) bool {
 	return T == nil || x.assignableTo(check.conf, T)
 }
 
+// narrowing returns a parenthesized note, for use in error messages,
+// naming the first method of interface type T that interface type V
+// lacks, or the empty string if V and T are not both interfaces or V
+// has all the methods of T. A value of type V may be converted to T
+// only by a type assertion.
+func narrowing(V, T Type) string {
+	Vi, _ := V.Underlying().(*Interface)
+	Ti, _ := T.Underlying().(*Interface)
+	if Vi == nil || Ti == nil {
+		return ""
+	}
+	m, wrongType := MissingMethod(Vi, Ti, true)
+	switch {
+	case m == nil:
+		return ""
+	case wrongType:
+		return fmt.Sprintf(" (wrong type for method %s)", m.name)
+	}
+	return fmt.Sprintf(" (missing method %s; use a type assertion)", m.name)
+}
+
 func (check *Checker) initConst(lhs *Const, x *operand) {
 	if x.mode == invalid || x.typ == Typ[Invalid] || lhs.typ == Typ[Invalid] {
 		if lhs.typ == nil {
@@ -117,7 +139,7 @@ func (check *Checker) initVar(lhs *Var, x *operand, result bool) Type {
 				lhs.typ = Typ[Invalid]
 				return nil
 			}
-			typ = defaultType(typ)
+			typ = DefaultType(typ)
 		}
 		lhs.typ = typ
 	}
@@ -126,9 +148,9 @@ func (check *Checker) initVar(lhs *Var, x *operand, result bool) Type {
 		if x.mode != invalid {
 			if result {
 				// don't refer to lhs.name because it may be an anonymous result parameter
-				check.errorf(x.pos(), "cannot return %s as value of type %s", x, lhs.typ)
+				check.errorf(x.pos(), "cannot return %s as value of type %s%s", x, lhs.typ, narrowing(x.typ, lhs.typ))
 			} else {
-				check.errorf(x.pos(), "cannot initialize %s with %s", lhs, x)
+				check.errorf(x.pos(), "cannot initialize %s with %s%s", lhs, x, narrowing(x.typ, lhs.typ))
 			}
 		}
 		return nil
@@ -193,7 +215,7 @@ func (check *Checker) assignVar(lhs ast.Expr, x *operand) Type {
 
 	if !check.assignment(x, z.typ) {
 		if x.mode != invalid {
-			check.errorf(x.pos(), "cannot assign %s to %s", x, &z)
+			check.errorf(x.pos(), "cannot assign %s to %s%s", x, &z, narrowing(x.typ, z.typ))
 		}
 		return nil
 	}
@@ -241,7 +241,7 @@ func (check *Checker) builtin(x *operand, call *ast.CallExpr, id builtinId) (_ b
 			complexT = Typ[Complex128]
 		case UntypedInt, UntypedRune, UntypedFloat:
 			if x.mode == constant {
-				realT = defaultType(realT).(*Basic)
+				realT = DefaultType(realT).(*Basic)
 				complexT = Typ[UntypedComplex]
 			} else {
 				// untyped but not constant; probably because one
@@ -320,8 +320,11 @@ func (check *Checker) builtin(x *operand, call *ast.CallExpr, id builtinId) (_ b
 			return
 		}
 
-		if !x.assignableTo(check.conf, m.key) {
-			check.invalidArg(x.pos(), "%s is not assignable to %s", x, m.key)
+		// an untyped key is given its final type, e.g. delete(m, 1) for a map[int8]T
+		if !check.assignment(x, m.key) {
+			if x.mode != invalid {
+				check.invalidArg(x.pos(), "%s is not assignable to %s", x, m.key)
+			}
 			return
 		}
 
@@ -467,6 +470,12 @@ func (check *Checker) builtin(x *operand, call *ast.CallExpr, id builtinId) (_ b
 			assert(x.mode == invalid)
 			return
 		}
+		if x.typ == Typ[Invalid] {
+			// error reported elsewhere (e.g., an initialization
+			// cycle); don't make up a value
+			x.mode = invalid
+			return
+		}
 
 		x.mode = constant
 		x.val = exact.MakeInt64(check.conf.alignof(x.typ))
@@ -522,6 +531,12 @@ func (check *Checker) builtin(x *operand, call *ast.CallExpr, id builtinId) (_ b
 			assert(x.mode == invalid)
 			return
 		}
+		if x.typ == Typ[Invalid] {
+			// error reported elsewhere (e.g., an initialization
+			// cycle); don't make up a value
+			x.mode = invalid
+			return
+		}
 
 		x.mode = constant
 		x.val = exact.MakeInt64(check.conf.sizeof(x.typ))
@@ -578,7 +593,7 @@ func (check *Checker) builtin(x *operand, call *ast.CallExpr, id builtinId) (_ b
 func makeSig(res Type, args ...Type) *Signature {
 	list := make([]*Var, len(args))
 	for i, param := range args {
-		list[i] = NewVar(token.NoPos, nil, "", defaultType(param))
+		list[i] = NewVar(token.NoPos, nil, "", DefaultType(param))
 	}
 	params := NewTuple(list...)
 	var result *Tuple
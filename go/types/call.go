@@ -235,7 +235,7 @@ func (check *Checker) argument(sig *Signature, i int, x *operand, passSlice bool
 	}
 
 	if !check.assignment(x, typ) && x.mode != invalid {
-		check.errorf(x.pos(), "cannot pass argument %s to parameter of type %s", x, typ)
+		check.errorf(x.pos(), "cannot pass argument %s to parameter of type %s%s", x, typ, narrowing(x.typ, typ))
 	}
 }
 
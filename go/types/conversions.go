@@ -38,7 +38,12 @@ func (check *Checker) conversion(x *operand, T Type) {
 	}
 
 	if !ok {
-		check.errorf(x.pos(), "cannot convert %s to %s", x, T)
+		if constArg && x.isInteger() && isInteger(T) {
+			// integer constant out of range, e.g. uint8(-1)
+			check.errorf(x.pos(), "constant %s overflows %s%s", x.val, T, check.rangeHint(T))
+		} else {
+			check.errorf(x.pos(), "cannot convert %s to %s%s", x, T, narrowing(x.typ, T))
+		}
 		x.mode = invalid
 		return
 	}
@@ -55,7 +60,7 @@ func (check *Checker) conversion(x *operand, T Type) {
 		//   not []byte as type for the constant "foo").
 		// - Keep untyped nil for untyped nil arguments.
 		if isInterface(T) || constArg && !isConstType(T) {
-			final = defaultType(x.typ)
+			final = DefaultType(x.typ)
 		}
 		check.updateExprType(x.expr, final, true)
 	}
@@ -11,6 +11,7 @@ import (
 	"go/ast"
 	"go/token"
 	"math"
+	"strings"
 
 	"code.google.com/p/go.tools/go/exact"
 )
@@ -85,7 +86,11 @@ func (check *Checker) unary(x *operand, op token.Token) {
 		// spec: "As an exception to the addressability
 		// requirement x may also be a composite literal."
 		if _, ok := unparen(x.expr).(*ast.CompositeLit); !ok && x.mode != variable {
-			check.invalidOp(x.pos(), "cannot take address of %s", x)
+			if x.mode == mapindex {
+				check.invalidOp(x.pos(), "cannot take address of map element %s", x.expr)
+			} else {
+				check.invalidOp(x.pos(), "cannot take address of %s", x)
+			}
 			x.mode = invalid
 			return
 		}
@@ -126,7 +131,7 @@ func (check *Checker) unary(x *operand, op token.Token) {
 		// Typed constants must be representable in
 		// their type after each constant operation.
 		if isTyped(typ) {
-			check.representable(x, typ)
+			check.representable(x, x.typ)
 		}
 		return
 	}
@@ -195,7 +200,7 @@ func representableConst(x exact.Value, conf *Config, as BasicKind, rounded *exac
 		if x, ok := exact.Int64Val(x); ok {
 			switch as {
 			case Int:
-				var s = uint(conf.sizeof(Typ[as])) * 8
+				var s = conf.intBits(Typ[as])
 				return int64(-1)<<(s-1) <= x && x <= int64(1)<<(s-1)-1
 			case Int8:
 				const s = 8
@@ -209,7 +214,7 @@ func representableConst(x exact.Value, conf *Config, as BasicKind, rounded *exac
 			case Int64:
 				return true
 			case Uint, Uintptr:
-				if s := uint(conf.sizeof(Typ[as])) * 8; s < 64 {
+				if s := conf.intBits(Typ[as]); s < 64 {
 					return 0 <= x && x <= int64(1)<<s-1
 				}
 				return 0 <= x
@@ -224,8 +229,12 @@ func representableConst(x exact.Value, conf *Config, as BasicKind, rounded *exac
 				return 0 <= x && x <= 1<<s-1
 			case Uint64:
 				return 0 <= x
-			case Float32, Float64, Complex64, Complex128,
-				UntypedInt, UntypedFloat, UntypedComplex:
+			case Float32, Float64, Complex64, Complex128:
+				// x always fits, but may need rounding (see below)
+				if rounded == nil {
+					return true
+				}
+			case UntypedInt, UntypedFloat, UntypedComplex:
 				return true
 			}
 		}
@@ -233,11 +242,16 @@ func representableConst(x exact.Value, conf *Config, as BasicKind, rounded *exac
 		n := exact.BitLen(x)
 		switch as {
 		case Uint, Uintptr:
-			var s = uint(conf.sizeof(Typ[as])) * 8
+			var s = conf.intBits(Typ[as])
 			return exact.Sign(x) >= 0 && n <= int(s)
 		case Uint64:
 			return exact.Sign(x) >= 0 && n <= 64
 		case Float32, Complex64:
+			// Integers that fit in the mantissa are exact; larger
+			// ones are rounded and only fail to fit if they overflow.
+			if n <= 24 {
+				return true
+			}
 			if rounded == nil {
 				return fitsFloat32(x)
 			}
@@ -247,6 +261,9 @@ func representableConst(x exact.Value, conf *Config, as BasicKind, rounded *exac
 				return true
 			}
 		case Float64, Complex128:
+			if n <= 53 {
+				return true
+			}
 			if rounded == nil {
 				return fitsFloat64(x)
 			}
@@ -319,10 +336,51 @@ func representableConst(x exact.Value, conf *Config, as BasicKind, rounded *exac
 	return false
 }
 
-// representable checks that a constant operand is representable in the given basic type.
-func (check *Checker) representable(x *operand, typ *Basic) {
+// intBits returns the number of bits of the typed integer type typ
+// for the purpose of constant representability. The sizes of int,
+// uint, and uintptr are determined by conf.
+func (conf *Config) intBits(typ *Basic) uint {
+	bits := uint(conf.sizeof(typ)) * 8
+	if conf.PortabilityMode && bits > 32 {
+		switch typ.kind {
+		case Int, Uint, Uintptr:
+			bits = 32
+		}
+	}
+	return bits
+}
+
+// intRange returns the smallest and largest value of the typed integer
+// type typ. The sizes of int, uint, and uintptr are determined by conf.
+func intRange(conf *Config, typ *Basic) (min, max exact.Value) {
+	bits := conf.intBits(typ)
+	one := exact.MakeInt64(1)
+	if isUnsigned(typ) {
+		min = exact.MakeInt64(0)
+		max = exact.BinaryOp(exact.Shift(one, token.SHL, bits), token.SUB, one)
+	} else {
+		min = exact.UnaryOp(token.SUB, exact.Shift(one, token.SHL, bits-1), 0)
+		max = exact.BinaryOp(exact.Shift(one, token.SHL, bits-1), token.SUB, one)
+	}
+	return
+}
+
+// rangeHint returns a description of the valid range of the typed
+// integer type typ for use in overflow errors, or the empty string.
+func (check *Checker) rangeHint(typ Type) string {
+	if t, _ := typ.Underlying().(*Basic); t != nil && isInteger(t) && isTyped(t) {
+		min, max := intRange(check.conf, t)
+		return fmt.Sprintf(" (valid range %s..%s)", min, max)
+	}
+	return ""
+}
+
+// representable checks that a constant operand is representable in the given
+// type, which must have a basic underlying type. Errors are reported using typ,
+// which may be a named type.
+func (check *Checker) representable(x *operand, typ Type) {
 	assert(x.mode == constant)
-	if !representableConst(x.val, check.conf, typ.kind, &x.val) {
+	if !representableConst(x.val, check.conf, typ.Underlying().(*Basic).kind, &x.val) {
 		var msg string
 		if isNumeric(x.typ) && isNumeric(typ) {
 			// numeric conversion : error msg
@@ -332,11 +390,16 @@ func (check *Checker) representable(x *operand, typ *Basic) {
 			// float   -> integer : truncated
 			// float   -> float   : overflows
 			//
+			var hint string
 			if !isInteger(x.typ) && isInteger(typ) {
 				msg = "%s truncated to %s"
 			} else {
 				msg = "%s overflows %s"
+				if isInteger(x.typ) {
+					hint = check.rangeHint(typ)
+				}
 			}
+			msg += hint
 		} else {
 			msg = "cannot convert %s to %s"
 		}
@@ -490,7 +553,7 @@ func (check *Checker) convertUntyped(x *operand, target Type) {
 	switch t := target.Underlying().(type) {
 	case *Basic:
 		if x.mode == constant {
-			check.representable(x, t)
+			check.representable(x, target)
 			if x.mode == invalid {
 				return
 			}
@@ -542,7 +605,7 @@ func (check *Checker) convertUntyped(x *operand, target Type) {
 			if !t.Empty() {
 				goto Error
 			}
-			target = defaultType(x.typ)
+			target = DefaultType(x.typ)
 		}
 	case *Pointer, *Signature, *Slice, *Map, *Chan:
 		if !x.isNil() {
@@ -569,21 +632,36 @@ func (check *Checker) comparison(x, y *operand, op token.Token) {
 	err := ""
 	if x.assignableTo(check.conf, y.typ) || y.assignableTo(check.conf, x.typ) {
 		defined := false
+		typ := x.typ // operand type for which op is not defined
 		switch op {
 		case token.EQL, token.NEQ:
 			// spec: "The equality operators == and != apply to operands that are comparable."
-			defined = Comparable(x.typ) || x.isNil() && hasNil(y.typ) || y.isNil() && hasNil(x.typ)
+			// A non-interface operand compared against an interface must itself be
+			// comparable, so both operand types must be comparable.
+			switch {
+			case x.isNil() || y.isNil():
+				if x.isNil() {
+					typ = y.typ
+				}
+				// (Comparable is true for invalid types, to avoid follow-up errors)
+				defined = hasNil(typ) || Comparable(typ)
+			case !Comparable(x.typ):
+				// typ == x.typ
+			case !Comparable(y.typ):
+				typ = y.typ
+			default:
+				defined = true
+			}
 		case token.LSS, token.LEQ, token.GTR, token.GEQ:
 			// spec: The ordering operators <, <=, >, and >= apply to operands that are ordered."
 			defined = isOrdered(x.typ)
+			if x.isNil() {
+				typ = y.typ
+			}
 		default:
 			unreachable()
 		}
 		if !defined {
-			typ := x.typ
-			if x.isNil() {
-				typ = y.typ
-			}
 			err = check.sprintf("operator %s not defined for %s", op, typ)
 		}
 	} else {
@@ -606,8 +684,8 @@ func (check *Checker) comparison(x, y *operand, op token.Token) {
 		// time will be materialized. Update the expression trees.
 		// If the current types are untyped, the materialized type
 		// is the respective default type.
-		check.updateExprType(x.expr, defaultType(x.typ), true)
-		check.updateExprType(y.expr, defaultType(y.typ), true)
+		check.updateExprType(x.expr, DefaultType(x.typ), true)
+		check.updateExprType(y.expr, DefaultType(y.typ), true)
 	}
 
 	// spec: "Comparison operators compare two operands and yield
@@ -644,13 +722,23 @@ func (check *Checker) shift(x, y *operand, op token.Token) {
 		return
 	}
 
+	// constant rhs must be >= 0
+	if y.mode == constant && exact.Sign(y.val) < 0 {
+		check.invalidOp(y.pos(), "negative shift count %s", y)
+		x.mode = invalid
+		return
+	}
+
 	if x.mode == constant {
 		if y.mode == constant {
 			// rhs must be within reasonable bounds
-			const stupidShift = 1023 - 1 + 52 // so we can express smallestFloat64
+			stupidShift := uint64(defaultMaxShift)
+			if max := check.conf.MaxShift; max > 0 {
+				stupidShift = uint64(max)
+			}
 			s, ok := exact.Uint64Val(y.val)
 			if !ok || s > stupidShift {
-				check.invalidOp(y.pos(), "stupid shift count %s", y)
+				check.invalidOp(y.pos(), "stupid shift count %s (limit %d)", y, stupidShift)
 				x.mode = invalid
 				return
 			}
@@ -662,6 +750,14 @@ func (check *Checker) shift(x, y *operand, op token.Token) {
 				x.typ = Typ[UntypedInt]
 			}
 			x.val = exact.Shift(x.val, op, uint(s))
+			if check.tooLarge(x) {
+				return
+			}
+			// Typed constants must be representable in
+			// their type after each constant operation.
+			if isTyped(x.typ) {
+				check.representable(x, x.typ)
+			}
 			return
 		}
 
@@ -685,11 +781,6 @@ func (check *Checker) shift(x, y *operand, op token.Token) {
 		}
 	}
 
-	// constant rhs must be >= 0
-	if y.mode == constant && exact.Sign(y.val) < 0 {
-		check.invalidOp(y.pos(), "shift count %s must not be negative", y)
-	}
-
 	// non-constant shift - lhs must be an integer
 	if !isInteger(x.typ) {
 		check.invalidOp(x.pos(), "shifted operand %s must be integer", x)
@@ -736,6 +827,17 @@ func (check *Checker) binary(x *operand, lhs, rhs ast.Expr, op token.Token) {
 		return
 	}
 
+	if isComparison(op) {
+		// Report untyped constants that cannot be represented in the
+		// type of the other operand as comparison errors, rather than
+		// as conversion errors from convertUntyped.
+		if c, typ := untypedNumericConst(x, &y); c != nil && !representableConst(c.val, check.conf, typ.Underlying().(*Basic).kind, nil) {
+			check.errorf(c.pos(), "cannot compare %s %s %s: %s is not representable as %s", x.expr, op, y.expr, c.expr, typ)
+			x.mode = invalid
+			return
+		}
+	}
+
 	check.convertUntyped(x, y.typ)
 	if x.mode == invalid {
 		return
@@ -779,10 +881,13 @@ func (check *Checker) binary(x *operand, lhs, rhs ast.Expr, op token.Token) {
 			op = token.QUO_ASSIGN
 		}
 		x.val = exact.BinaryOp(x.val, op, y.val)
+		if check.tooLarge(x) {
+			return
+		}
 		// Typed constants must be representable in
 		// their type after each constant operation.
 		if isTyped(typ) {
-			check.representable(x, typ)
+			check.representable(x, x.typ)
 		}
 		return
 	}
@@ -791,6 +896,67 @@ func (check *Checker) binary(x *operand, lhs, rhs ast.Expr, op token.Token) {
 	// x.typ is unchanged
 }
 
+// defaultMaxShift is the constant shift count limit used if
+// Config.MaxShift is not set.
+const defaultMaxShift = 1023 - 1 + 52 // so we can express smallestFloat64
+
+// defaultMaxConstBits is the constant size limit used if
+// Config.MaxConstBits is not set. It is large enough to
+// accommodate any constant representable in a Go variable
+// while keeping pathological constant expressions cheap.
+const defaultMaxConstBits = 10000
+
+// constBits returns the number of bits needed to represent
+// the (numeric) constant value x.
+func constBits(x exact.Value) int {
+	switch x.Kind() {
+	case exact.Int:
+		return exact.BitLen(x)
+	case exact.Float:
+		n := exact.BitLen(exact.Num(x))
+		if d := exact.BitLen(exact.Denom(x)); d > n {
+			n = d
+		}
+		return n
+	case exact.Complex:
+		n := constBits(exact.Real(x))
+		if i := constBits(exact.Imag(x)); i > n {
+			n = i
+		}
+		return n
+	}
+	return 0
+}
+
+// tooLarge reports whether the constant operand x exceeds the
+// configured constant size limit. If so, an error is reported
+// and x is invalidated.
+func (check *Checker) tooLarge(x *operand) bool {
+	max := check.conf.MaxConstBits
+	if max <= 0 {
+		max = defaultMaxConstBits
+	}
+	if constBits(x.val) > max {
+		check.errorf(x.pos(), "constant too large")
+		x.mode = invalid
+		return true
+	}
+	return false
+}
+
+// untypedNumericConst returns the operand of x and y that is an untyped
+// numeric constant, and the type of the other operand, if that is a typed
+// numeric type. Otherwise the result is nil, nil.
+func untypedNumericConst(x, y *operand) (*operand, Type) {
+	if x.mode == constant && isUntyped(x.typ) && isNumeric(x.typ) && isTyped(y.typ) && isNumeric(y.typ) {
+		return x, y.typ
+	}
+	if y.mode == constant && isUntyped(y.typ) && isNumeric(y.typ) && isTyped(x.typ) && isNumeric(x.typ) {
+		return y, x.typ
+	}
+	return nil, nil
+}
+
 // index checks an index expression for validity.
 // If max >= 0, it is the upper bound for index.
 // If index is valid and the result i >= 0, then i is the constant value of index.
@@ -816,14 +982,18 @@ func (check *Checker) index(index ast.Expr, max int64) (i int64, valid bool) {
 	// a constant index i must be in bounds
 	if x.mode == constant {
 		if exact.Sign(x.val) < 0 {
-			check.invalidArg(x.pos(), "index %s must not be negative", &x)
+			check.errorf(x.pos(), "index %s is out of bounds (must not be negative)", &x)
 			return
 		}
 		i, valid = exact.Int64Val(x.val)
-		if !valid || max >= 0 && i >= max {
+		if !valid {
 			check.errorf(x.pos(), "index %s is out of bounds", &x)
 			return i, false
 		}
+		if max >= 0 && i >= max {
+			check.errorf(x.pos(), "index %s is out of bounds (>= %d)", &x, max)
+			return i, false
+		}
 		// 0 <= i [ && i < max ]
 		return i, true
 	}
@@ -989,6 +1159,10 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 					// We have an "open" [...]T array type.
 					// Create a new ArrayType with unknown length (-1)
 					// and finish setting it up after analyzing the literal.
+					// The elements cannot refer to this type (and thus its
+					// unknown length): the literal has no name, and a
+					// reference to a variable initialized with it is an
+					// initialization cycle, reported elsewhere.
 					typ = &Array{len: -1, elem: check.typ(atyp.Elt)}
 					openArray = true
 				}
@@ -1024,7 +1198,11 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 					}
 					i := fieldIndex(utyp.fields, check.pkg, key.Name)
 					if i < 0 {
-						check.errorf(kv.Pos(), "unknown field %s in struct literal", key.Name)
+						if fld := unexportedField(utyp.fields, key.Name); fld != nil {
+							check.errorf(kv.Pos(), "cannot refer to unexported field %s in struct literal of package %s", key.Name, fld.pkg.name)
+						} else {
+							check.errorf(kv.Pos(), "unknown field %s in struct literal", key.Name)
+						}
 						continue
 					}
 					fld := fields[i]
@@ -1066,7 +1244,11 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 					}
 				}
 				if len(e.Elts) < len(fields) {
-					check.error(e.Rbrace, "too few values in struct literal")
+					var missing []string
+					for _, f := range fields[len(e.Elts):] {
+						missing = append(missing, f.name)
+					}
+					check.errorf(e.Rbrace, "too few values in struct literal (missing %s)", strings.Join(missing, ", "))
 					// ok to continue
 				}
 			}
@@ -1075,6 +1257,7 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 			n := check.indexedElts(e.Elts, utyp.elem, utyp.len)
 			// if we have an "open" [...]T array, set the length now that we know it
 			if openArray {
+				assert(utyp.len < 0)
 				utyp.len = n
 			}
 
@@ -1082,7 +1265,10 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 			check.indexedElts(e.Elts, utyp.elem, -1)
 
 		case *Map:
-			visited := make(map[interface{}][]Type, len(e.Elts))
+			// Constant keys are compared via their (exact) string
+			// representation: equal values of arbitrary precision
+			// are not necessarily equal exact.Values.
+			visited := make(map[string][]Type, len(e.Elts))
 			for _, e := range e.Elts {
 				kv, _ := e.(*ast.KeyValueExpr)
 				if kv == nil {
@@ -1098,18 +1284,19 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 				}
 				if x.mode == constant {
 					duplicate := false
+					key := x.val.String()
 					// if the key is of interface type, the type is also significant when checking for duplicates
 					if _, ok := utyp.key.Underlying().(*Interface); ok {
-						for _, vtyp := range visited[x.val] {
+						for _, vtyp := range visited[key] {
 							if Identical(vtyp, x.typ) {
 								duplicate = true
 								break
 							}
 						}
-						visited[x.val] = append(visited[x.val], x.typ)
+						visited[key] = append(visited[key], x.typ)
 					} else {
-						_, duplicate = visited[x.val]
-						visited[x.val] = nil
+						_, duplicate = visited[key]
+						visited[key] = nil
 					}
 					if duplicate {
 						check.errorf(x.pos(), "duplicate key %s in map literal", x.val)
@@ -1242,7 +1429,13 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 			valid = true
 			length = typ.len
 			if x.mode != variable {
-				check.invalidOp(x.pos(), "cannot slice %s (value not addressable)", x)
+				// Composite literals are not addressable either; only
+				// &T{} is permitted as an exception (see unary).
+				hint := "assign it to a variable first"
+				if _, ok := unparen(x.expr).(*ast.CompositeLit); ok {
+					hint = "take its address with &"
+				}
+				check.invalidOp(x.pos(), "cannot slice %s (value not addressable; %s)", x, hint)
 				goto Error
 			}
 			x.typ = &Slice{elem: typ.elem}
@@ -1319,7 +1512,7 @@ func (check *Checker) exprInternal(x *operand, e ast.Expr, hint Type) exprKind {
 		}
 		xtyp, _ := x.typ.Underlying().(*Interface)
 		if xtyp == nil {
-			check.invalidOp(x.pos(), "%s is not an interface", x)
+			check.invalidOp(x.pos(), "%s is not an interface; type assertions require an operand of interface type", x)
 			goto Error
 		}
 		// x.(type) expressions are handled explicitly in type switches
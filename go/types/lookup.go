@@ -346,6 +346,18 @@ func fieldIndex(fields []*Var, pkg *Package, name string) int {
 	return -1
 }
 
+// unexportedField returns the unexported field with the given name
+// among fields, or nil. It is used to explain why fieldIndex failed to
+// find a field that is declared in another package.
+func unexportedField(fields []*Var, name string) *Var {
+	for _, f := range fields {
+		if f.name == name && !f.Exported() && f.pkg != nil {
+			return f
+		}
+	}
+	return nil
+}
+
 // lookupMethod returns the index of and method with matching package and name, or (-1, nil).
 func lookupMethod(methods []*Func, pkg *Package, name string) (int, *Func) {
 	if name != "_" {
@@ -265,9 +265,18 @@ func (x *operand) assignableTo(conf *Config, T Type) bool {
 				return representableConst(x.val, conf, t.kind, nil)
 			}
 			// The result of a comparison is an untyped boolean,
-			// but may not be a constant.
+			// but may not be a constant. Other non-constant untyped
+			// values only arise via AssignableTo; like convertUntyped,
+			// accept them if T is of the same kind.
 			if Vb, _ := Vu.(*Basic); Vb != nil {
-				return Vb.kind == UntypedBool && isBoolean(Tu)
+				switch {
+				case Vb.kind == UntypedBool:
+					return isBoolean(Tu)
+				case isNumeric(Vb):
+					return isNumeric(Tu)
+				case isString(Vb):
+					return isString(Tu)
+				}
 			}
 		case *Interface:
 			return x.isNil() || t.Empty()
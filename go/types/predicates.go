@@ -61,6 +61,14 @@ func isUntyped(typ Type) bool {
 	return ok && t.info&IsUntyped != 0
 }
 
+// Ordered reports whether values of type T are ordered, i.e.,
+// whether they may be operands of the ordering operators <, <=,
+// > and >=. Only integer, floating-point and string types, and
+// named types with such an underlying type, are ordered.
+func Ordered(T Type) bool {
+	return isOrdered(T)
+}
+
 func isOrdered(typ Type) bool {
 	t, ok := typ.Underlying().(*Basic)
 	return ok && t.info&IsOrdered != 0
@@ -77,6 +85,10 @@ func isInterface(typ Type) bool {
 }
 
 // Comparable reports whether values of type T are comparable.
+// The result depends only on T's underlying type: booleans, numbers,
+// strings, pointers, channels and interfaces are comparable; structs
+// are comparable if all their fields are, and arrays if their element
+// type is; slices, maps and functions are not.
 func Comparable(T Type) bool {
 	switch t := T.Underlying().(type) {
 	case *Basic:
@@ -283,11 +295,15 @@ func identical(x, y Type, p *ifacePair) bool {
 	return false
 }
 
-// defaultType returns the default "typed" type for an "untyped" type;
-// it returns the incoming type for all other types. The default type
-// for untyped nil is untyped nil.
+// DefaultType returns the default "typed" type for an "untyped" type;
+// it returns the incoming type for all other types. This is the type
+// an untyped constant assumes when it is used in a context that does
+// not determine its type, as in x := 1 or interface{}(1.5).
+//
+// Untyped nil has no default type; DefaultType(Typ[UntypedNil]) is
+// Typ[UntypedNil].
 //
-func defaultType(typ Type) Type {
+func DefaultType(typ Type) Type {
 	if t, ok := typ.(*Basic); ok {
 		switch t.kind {
 		case UntypedBool:
@@ -13,8 +13,9 @@ import (
 	"code.google.com/p/go.tools/oracle/serial"
 )
 
-// Callstack displays an arbitrary path from a root of the callgraph
-// to the function at the current position.
+// Callstack displays a shortest path from a root of the callgraph
+// to the function at the current position, and its length, the call
+// depth of the function from an entry point (main or init).
 //
 // The information may be misleading in a context-insensitive
 // analysis. e.g. the call path X->Y->Z might be infeasible if Y never
@@ -45,9 +46,8 @@ func callstack(o *Oracle, qpos *QueryPos) (queryResult, error) {
 	cg := ptrAnalysis(o).CallGraph
 	cg.DeleteSyntheticNodes()
 
-	// Search for an arbitrary path from a root to the target function.
-	isEnd := func(n *callgraph.Node) bool { return n.Func == target }
-	callpath := callgraph.PathSearch(cg.Root, isEnd)
+	// Search for a shortest path from a root to the target function.
+	callpath := shortestPath(cg.Root, target)
 	if callpath != nil {
 		callpath = callpath[1:] // remove synthetic edge from <root>
 	}
@@ -59,6 +59,34 @@ func callstack(o *Oracle, qpos *QueryPos) (queryResult, error) {
 	}, nil
 }
 
+// shortestPath returns a shortest path of edges from start to a node
+// for function target, found by breadth-first search, or nil if there
+// is none.
+//
+func shortestPath(start *callgraph.Node, target *ssa.Function) []*callgraph.Edge {
+	pred := map[*callgraph.Node]*callgraph.Edge{start: nil}
+	for queue := []*callgraph.Node{start}; len(queue) > 0; queue = queue[1:] {
+		n := queue[0]
+		if n.Func == target {
+			var path []*callgraph.Edge
+			for e := pred[n]; e != nil; e = pred[e.Caller] {
+				path = append(path, e)
+			}
+			for i, j := 0, len(path)-1; i < j; i, j = i+1, j-1 {
+				path[i], path[j] = path[j], path[i]
+			}
+			return path
+		}
+		for _, e := range n.Out {
+			if _, seen := pred[e.Callee]; !seen {
+				pred[e.Callee] = e
+				queue = append(queue, e.Callee)
+			}
+		}
+	}
+	return nil
+}
+
 type callstackResult struct {
 	qpos     *QueryPos
 	target   *ssa.Function
@@ -67,7 +95,7 @@ type callstackResult struct {
 
 func (r *callstackResult) display(printf printfFunc) {
 	if r.callpath != nil {
-		printf(r.qpos, "Found a call path from root to %s", r.target)
+		printf(r.qpos, "Found a call path of depth %d from root to %s", len(r.callpath), r.target)
 		printf(r.target, "%s", r.target)
 		for i := len(r.callpath) - 1; i >= 0; i-- {
 			edge := r.callpath[i]
@@ -88,9 +116,14 @@ func (r *callstackResult) toSerial(res *serial.Result, fset *token.FileSet) {
 			Desc:   edge.Description(),
 		})
 	}
+	depth := -1
+	if r.callpath != nil {
+		depth = len(r.callpath)
+	}
 	res.Callstack = &serial.CallStack{
 		Pos:     fset.Position(r.target.Pos()).String(),
 		Target:  r.target.String(),
 		Callers: callers,
+		Depth:   depth,
 	}
 }
@@ -0,0 +1,144 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package oracle
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"code.google.com/p/go.tools/astutil"
+	"code.google.com/p/go.tools/go/exact"
+	"code.google.com/p/go.tools/go/types"
+	"code.google.com/p/go.tools/oracle/serial"
+)
+
+// convert reports whether the selected expression is assignable to
+// the type T given by the mode argument ("convert=T"), and if not,
+// the explicit conversion, if any, that would make it so.
+// T is evaluated in the innermost scope enclosing the selection.
+// Untyped constant expressions are judged by their untyped type and
+// value, e.g. 256 is assignable to int but not convertible to uint8.
+func convert(o *Oracle, qpos *QueryPos) (queryResult, error) {
+	path, action := findInterestingNode(qpos.info, qpos.path)
+	if action != actionExpr {
+		return nil, fmt.Errorf("convert wants an expression; got %s",
+			astutil.NodeDescription(qpos.path[0]))
+	}
+	expr, ok := path[0].(ast.Expr)
+	if !ok {
+		return nil, fmt.Errorf("convert wants an expression; got %s",
+			astutil.NodeDescription(path[0]))
+	}
+	V := qpos.info.TypeOf(expr)
+	if V == nil {
+		return nil, fmt.Errorf("no type for %s", astutil.NodeDescription(expr))
+	}
+
+	// Find the innermost scope enclosing the selection.
+	// (A function's outermost scope is associated with its type.)
+	var scope *types.Scope
+	for _, n := range path {
+		switch f := n.(type) {
+		case *ast.FuncDecl:
+			n = f.Type
+		case *ast.FuncLit:
+			n = f.Type
+		}
+		if s := qpos.info.Scopes[n]; s != nil {
+			scope = s
+			break
+		}
+	}
+	T, _, err := types.Eval(o.arg, qpos.info.Pkg, scope)
+	if err != nil {
+		return nil, fmt.Errorf("invalid target type %q: %s", o.arg, err)
+	}
+
+	// The type recorded for a constant expression is that required
+	// by its context, e.g. int for 1 in "_ = 1".  Re-evaluate it to
+	// recover its own (possibly untyped) type.
+	var val exact.Value
+	if qpos.info.Types[expr].Value != nil {
+		if U, v, err := types.EvalNode(qpos.fset, expr, qpos.info.Pkg, scope); err == nil {
+			V, val = U, v
+		}
+	}
+
+	var assignable, convertible bool
+	if b, ok := V.(*types.Basic); ok && b.Info()&types.IsUntyped != 0 && val != nil {
+		// An untyped constant must also be representable as a T,
+		// which is checked by evaluating the conversion T(expr).
+		_, _, err := types.Eval(conversionString("("+o.arg+")", expr), qpos.info.Pkg, scope)
+		convertible = err == nil
+		assignable = convertible && types.AssignableTo(V, T)
+	} else {
+		assignable = types.AssignableTo(V, T)
+		convertible = types.ConvertibleTo(V, T)
+	}
+
+	var conversion string
+	if !assignable && convertible {
+		conversion = conversionString(qpos.TypeString(T), expr)
+	}
+
+	return &convertResult{
+		qpos:       qpos,
+		expr:       expr,
+		typ:        V,
+		val:        val,
+		target:     T,
+		assignable: assignable,
+		conversion: conversion,
+	}, nil
+}
+
+// conversionString returns the source text of the conversion of
+// expression e to the type whose string form is typ, e.g. "T(e)".
+func conversionString(typ string, e ast.Expr) string {
+	// Parenthesize types that would otherwise parse differently.
+	if strings.HasPrefix(typ, "*") || strings.HasPrefix(typ, "<-") || strings.HasPrefix(typ, "func") {
+		typ = "(" + typ + ")"
+	}
+	return fmt.Sprintf("%s(%s)", typ, types.ExprString(e))
+}
+
+type convertResult struct {
+	qpos       *QueryPos
+	expr       ast.Expr    // selected expression
+	typ        types.Type  // type of expr
+	val        exact.Value // value of expr, if constant
+	target     types.Type  // target type
+	assignable bool        // expr is assignable to target
+	conversion string      // conversion to target, if needed and possible
+}
+
+func (r *convertResult) display(printf printfFunc) {
+	desc := fmt.Sprintf("%s of type %s", astutil.NodeDescription(r.expr), r.qpos.TypeString(r.typ))
+	if _, ok := r.expr.(*ast.BasicLit); !ok && r.val != nil {
+		desc += " and constant value " + constString(r.val)
+	}
+	target := r.qpos.TypeString(r.target)
+	switch {
+	case r.assignable:
+		printf(r.expr, "%s is assignable to %s", desc, target)
+	case r.conversion != "":
+		printf(r.expr, "%s is not assignable to %s; use the conversion %s", desc, target, r.conversion)
+	default:
+		printf(r.expr, "%s is neither assignable nor convertible to %s", desc, target)
+	}
+}
+
+func (r *convertResult) toSerial(res *serial.Result, fset *token.FileSet) {
+	res.Convert = &serial.Convert{
+		Pos:        fset.Position(r.expr.Pos()).String(),
+		Type:       r.qpos.TypeString(r.typ),
+		Value:      constString(r.val),
+		Target:     r.qpos.TypeString(r.target),
+		Assignable: r.assignable,
+		Conversion: r.conversion,
+	}
+}
@@ -11,6 +11,7 @@ import (
 	"go/token"
 	"log"
 	"os"
+	"sort"
 	"strings"
 
 	"code.google.com/p/go.tools/astutil"
@@ -33,26 +34,64 @@ func describe(o *Oracle, qpos *QueryPos) (queryResult, error) {
 			astutil.NodeDescription(qpos.path[0]), pathToString(qpos.path))
 	}
 
+	var res queryResult
+	var err error
 	path, action := findInterestingNode(qpos.info, qpos.path)
 	switch action {
 	case actionExpr:
-		return describeValue(o, qpos, path)
+		res, err = describeValue(o, qpos, path)
 
 	case actionType:
-		return describeType(o, qpos, path)
+		res, err = describeType(o, qpos, path)
 
 	case actionPackage:
-		return describePackage(o, qpos, path)
+		res, err = describePackage(o, qpos, path)
 
 	case actionStmt:
-		return describeStmt(o, qpos, path)
+		res, err = describeStmt(o, qpos, path)
 
 	case actionUnknown:
-		return &describeUnknownResult{path[0]}, nil
+		res = &describeUnknownResult{path[0]}
 
 	default:
 		panic(action) // unreachable
 	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Note whether the node belongs to test code.
+	if file := o.fset.File(qpos.start); file != nil && strings.HasSuffix(file.Name(), "_test.go") {
+		res = &describeTestResult{
+			queryResult: res,
+			node:        path[0],
+			xtest:       strings.HasSuffix(qpos.info.Pkg.Name(), "_test"),
+		}
+	}
+	return res, nil
+}
+
+// describeTestResult augments the result of a describe query for a
+// node in a _test.go file.
+type describeTestResult struct {
+	queryResult
+	node  ast.Node
+	xtest bool // file belongs to an external test package
+}
+
+func (r *describeTestResult) display(printf printfFunc) {
+	r.queryResult.display(printf)
+	if r.xtest {
+		printf(r.node, "in a test file of an external test package")
+	} else {
+		printf(r.node, "in a test file")
+	}
+}
+
+func (r *describeTestResult) toSerial(res *serial.Result, fset *token.FileSet) {
+	r.queryResult.toSerial(res, fset)
+	res.Describe.Test = true
+	res.Describe.XTest = r.xtest
 }
 
 type describeUnknownResult struct {
@@ -314,37 +353,122 @@ func describeValue(o *Oracle, qpos *QueryPos, path []ast.Node) (*describeValueRe
 
 	typ := qpos.info.TypeOf(expr)
 	constVal := qpos.info.Types[expr].Value
+	arrayLen := constVal != nil && isArrayLength(constVal, o.sizes())
+
+	var fits []string
+	if c, ok := obj.(*types.Const); ok {
+		fits = smallestIntTypes(c.Val())
+	} else if constVal != nil {
+		fits = smallestIntTypes(constVal)
+	}
+
+	var impls []*types.Selection
+	var ifaces []*types.TypeName
+	var returnsErr bool
+	if fn, ok := obj.(*types.Func); ok {
+		returnsErr = returnsError(fn.Type().(*types.Signature))
+		if isInterfaceMethod(fn) {
+			impls = implementations(qpos, fn)
+		} else if fn.Type().(*types.Signature).Recv() != nil && fn.Pos() == expr.Pos() {
+			// Only the method's definition lists the interfaces.
+			ifaces = interfacesWithMethod(qpos, fn)
+		}
+	}
+
+	pure := isPure(qpos.info, expr)
+
+	var reader, writer bool
+	if typ != nil {
+		reader = types.Implements(typ, readerType)
+		writer = types.Implements(typ, writerType)
+	}
+
+	var folds []foldStep
+	if obj == nil {
+		folds = foldingSteps(qpos.info, expr)
+	}
+
+	var recv string
+	if fn := concreteMethod(qpos, expr); fn != nil {
+		recv = "value"
+		if _, ok := fn.Type().(*types.Signature).Recv().Type().(*types.Pointer); ok {
+			recv = "pointer"
+		}
+	}
+
+	var deprecated string
+	if obj != nil {
+		deprecated = deprecation(o, qpos, obj)
+	}
+
+	stmt := callStmt(path)
+
+	var init *initInfo
+	staticSize := int64(-1)
+	if v, ok := obj.(*types.Var); ok && v.Pkg() != nil && v.Parent() == v.Pkg().Scope() && v.Pos() == expr.Pos() {
+		// Only the global's definition reports its size and initialization.
+		init = initDependencies(qpos.info, v)
+		staticSize = o.sizes().Sizeof(v.Type())
+	}
 
 	return &describeValueResult{
-		qpos:     qpos,
-		expr:     expr,
-		typ:      typ,
-		constVal: constVal,
-		obj:      obj,
+		qpos:       qpos,
+		expr:       expr,
+		typ:        typ,
+		constVal:   constVal,
+		arrayLen:   arrayLen,
+		fits:       fits,
+		obj:        obj,
+		impls:      impls,
+		ifaces:     ifaces,
+		returnsErr: returnsErr,
+		pure:       pure,
+		reader:     reader,
+		writer:     writer,
+		folds:      folds,
+		deprecated: deprecated,
+		recv:       recv,
+		stmt:       stmt,
+		init:       init,
+		staticSize: staticSize,
+		platform:   o.platform(),
 	}, nil
 }
 
 type describeValueResult struct {
-	qpos     *QueryPos
-	expr     ast.Expr     // query node
-	typ      types.Type   // type of expression
-	constVal exact.Value  // value of expression, if constant
-	obj      types.Object // var/func/const object, if expr was Ident
+	qpos       *QueryPos
+	expr       ast.Expr           // query node
+	typ        types.Type         // type of expression
+	constVal   exact.Value        // value of expression, if constant
+	arrayLen   bool               // constVal is a valid array length on the target
+	fits       []string           // smallest sized integer types representing the constant value, if an integer
+	obj        types.Object       // var/func/const object, if expr was Ident
+	impls      []*types.Selection // concrete methods implementing interface method obj
+	ifaces     []*types.TypeName  // named interfaces having a method like concrete method obj
+	returnsErr bool               // func obj's last result is of type error
+	pure       bool               // expr is free of side effects
+	reader     bool               // typ implements io.Reader
+	writer     bool               // typ implements io.Writer
+	folds      []foldStep         // constant folding steps of non-Ident expr, if constant
+	deprecated string             // deprecation notice of obj, if any
+	recv       string             // "value" or "pointer" receiver of the concrete method called by expr, if any
+	stmt       string             // "defer" or "go", if expr is the call of such a statement
+	init       *initInfo          // initialization of package-level var obj, if any
+	staticSize int64              // size of package-level var obj, or -1
+	platform   string             // GOOS/GOARCH assumed for sizes
 }
 
 func (r *describeValueResult) display(printf printfFunc) {
 	var prefix, suffix string
 	if r.constVal != nil {
 		suffix = fmt.Sprintf(" of constant value %s", r.constVal)
+		if r.arrayLen {
+			suffix += " (valid array length)"
+		}
 	}
-	switch obj := r.obj.(type) {
-	case *types.Func:
-		if recv := obj.Type().(*types.Signature).Recv(); recv != nil {
-			if _, ok := recv.Type().Underlying().(*types.Interface); ok {
-				prefix = "interface method "
-			} else {
-				prefix = "method "
-			}
+	if fn, ok := r.obj.(*types.Func); ok {
+		if kind := funcKind(fn); kind != "function" {
+			prefix = kind + " "
 		}
 	}
 
@@ -370,6 +494,79 @@ func (r *describeValueResult) display(printf printfFunc) {
 			printf(r.expr, "%s of type %s", desc, r.qpos.TypeString(r.typ))
 		}
 	}
+
+	if r.fits != nil {
+		printf(r.expr, "value fits in %s", strings.Join(r.fits, " and "))
+	}
+
+	if r.deprecated != "" {
+		printf(r.expr, "Deprecated: %s", r.deprecated)
+	}
+
+	if len(r.folds) > 0 {
+		printf(r.expr, "Constant folding steps:")
+		for _, step := range r.folds {
+			printf(r.expr, "\t%s -> %s", step.expr, constString(step.value))
+		}
+	}
+
+	switch {
+	case r.reader && r.writer:
+		printf(r.expr, "%s implements io.Reader and io.Writer", r.qpos.TypeString(r.typ))
+	case r.reader:
+		printf(r.expr, "%s implements io.Reader", r.qpos.TypeString(r.typ))
+	case r.writer:
+		printf(r.expr, "%s implements io.Writer", r.qpos.TypeString(r.typ))
+	}
+
+	switch r.recv {
+	case "pointer":
+		printf(r.expr, "pointer receiver: calls may mutate the receiver")
+	case "value":
+		printf(r.expr, "value receiver: calls operate on a copy of the receiver")
+	}
+
+	if r.returnsErr {
+		printf(r.obj, "%s returns an error as its last result", r.obj.Name())
+	}
+
+	switch r.stmt {
+	case "defer":
+		printf(r.expr, "call of defer statement: runs when the enclosing function returns")
+	case "go":
+		printf(r.expr, "call of go statement: runs in a new goroutine")
+	}
+
+	if len(r.impls) > 0 {
+		printf(r.obj, "Implemented by these %d concrete methods:", len(r.impls))
+		for _, meth := range r.impls {
+			printf(meth.Obj(), "\t%s", r.qpos.SelectionString(meth))
+		}
+	}
+
+	if len(r.ifaces) > 0 {
+		printf(r.obj, "Contributes to these %d interfaces:", len(r.ifaces))
+		for _, iface := range r.ifaces {
+			printf(iface, "\t%s", r.qpos.TypeString(iface.Type()))
+		}
+	}
+
+	if r.staticSize >= 0 {
+		printf(r.obj, "%s occupies %d bytes of static storage on %s", r.obj.Name(), r.staticSize, r.platform)
+	}
+
+	if init := r.init; init != nil {
+		printf(r.obj, "%s is initialized at step %d of %d of package initialization",
+			r.obj.Name(), init.step, init.steps)
+		if len(init.deps) == 0 {
+			printf(r.obj, "its initializer depends on no other package-level variables")
+		} else {
+			printf(r.obj, "its initializer depends on these %d package-level variables:", len(init.deps))
+			for _, dep := range init.deps {
+				printf(dep, "\t%s", dep.Name())
+			}
+		}
+	}
 }
 
 func (r *describeValueResult) toSerial(res *serial.Result, fset *token.FileSet) {
@@ -380,19 +577,446 @@ func (r *describeValueResult) toSerial(res *serial.Result, fset *token.FileSet)
 	if r.obj != nil {
 		objpos = fset.Position(r.obj.Pos()).String()
 	}
+	var kind string
+	if fn, ok := r.obj.(*types.Func); ok {
+		kind = funcKind(fn)
+	}
+	var init *serial.DescribeInit
+	if r.init != nil {
+		init = &serial.DescribeInit{Step: r.init.step}
+		for _, dep := range r.init.deps {
+			init.Deps = append(init.Deps, serial.DescribeInitDep{
+				Name: dep.Name(),
+				Pos:  fset.Position(dep.Pos()).String(),
+			})
+		}
+	}
+
+	var staticSize *int64
+	if r.staticSize >= 0 {
+		staticSize = &r.staticSize
+	}
+	var ifaces []serial.DescribeInterface
+	for _, iface := range r.ifaces {
+		ifaces = append(ifaces, serial.DescribeInterface{
+			Name: r.qpos.TypeString(iface.Type()),
+			Pos:  fset.Position(iface.Pos()).String(),
+		})
+	}
+	var folds []serial.DescribeFold
+	for _, step := range r.folds {
+		folds = append(folds, serial.DescribeFold{Expr: step.expr, Value: constString(step.value)})
+	}
 
 	res.Describe = &serial.Describe{
 		Desc:   astutil.NodeDescription(r.expr),
 		Pos:    fset.Position(r.expr.Pos()).String(),
 		Detail: "value",
 		Value: &serial.DescribeValue{
-			Type:   r.qpos.TypeString(r.typ),
-			Value:  value,
-			ObjPos: objpos,
+			Type:       r.qpos.TypeString(r.typ),
+			Kind:       kind,
+			Receiver:   r.recv,
+			Stmt:       r.stmt,
+			ReturnsErr: r.returnsErr,
+			Pure:       r.pure,
+			Reader:     r.reader,
+			Writer:     r.writer,
+			Value:      value,
+			Folds:      folds,
+			Deprecated: r.deprecated,
+			ObjPos:     objpos,
+			ArrayLen:   r.arrayLen,
+			FitsIn:     r.fits,
+			Impls:      methodsToSerial(r.qpos.info.Pkg, r.impls, fset),
+			Interfaces: ifaces,
+			Init:       init,
+			StaticSize: staticSize,
 		},
 	}
 }
 
+// isArrayLength reports whether the constant value v may be used as
+// the length of an array type, i.e. it is a non-negative integer
+// representable as an int of the target platform.
+func isArrayLength(v exact.Value, sizes types.Sizes) bool {
+	if v.Kind() != exact.Int {
+		return false
+	}
+	bits := uint(8 * sizes.Sizeof(types.Typ[types.Int]))
+	n, ok := exact.Int64Val(v)
+	return ok && n >= 0 && (bits >= 64 || n < 1<<(bits-1))
+}
+
+// smallestIntTypes returns the names of the smallest signed and the
+// smallest unsigned sized integer types (of int8 through uint64) that
+// can represent the constant value v, or nil if v is not an integer.
+// Either may be absent, e.g. for negative or very large values.
+func smallestIntTypes(v exact.Value) []string {
+	if v.Kind() != exact.Int {
+		return nil
+	}
+	var names []string
+	if n, ok := exact.Int64Val(v); ok {
+		for _, bits := range []uint{8, 16, 32, 64} {
+			if min, max := int64(-1)<<(bits-1), int64(1)<<(bits-1)-1; min <= n && n <= max {
+				names = append(names, fmt.Sprintf("int%d", bits))
+				break
+			}
+		}
+	}
+	if n, ok := exact.Uint64Val(v); ok {
+		for _, bits := range []uint{8, 16, 32, 64} {
+			if bits == 64 || n <= uint64(1)<<bits-1 {
+				names = append(names, fmt.Sprintf("uint%d", bits))
+				break
+			}
+		}
+	}
+	return names
+}
+
+// funcKind returns "function", "method" or "interface method"
+// according to the receiver of fn.
+func funcKind(fn *types.Func) string {
+	switch recv := fn.Type().(*types.Signature).Recv(); {
+	case recv == nil:
+		return "function"
+	case isInterface(recv.Type()):
+		return "interface method"
+	}
+	return "method"
+}
+
+// returnsError reports whether the last result of sig is of the
+// built-in type error.
+func returnsError(sig *types.Signature) bool {
+	res := sig.Results()
+	return res.Len() > 0 && types.Identical(res.At(res.Len()-1).Type(), types.Universe.Lookup("error").Type())
+}
+
+// isPure reports whether the evaluation of expression e is free of
+// side effects: it contains no channel receives and no function
+// calls other than conversions and calls of the built-ins that
+// merely compute a value, such as len.  The bodies of function
+// literals are not evaluated, so are not inspected.
+//
+// Such expressions may be safely duplicated or reordered, though
+// their evaluation may still panic, e.g. on a nil map or an
+// out-of-bounds index.
+//
+func isPure(info *loader.PackageInfo, e ast.Expr) bool {
+	pure := true
+	ast.Inspect(e, func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.FuncLit:
+			return false
+		case *ast.UnaryExpr:
+			if n.Op == token.ARROW {
+				pure = false
+			}
+		case *ast.CallExpr:
+			if info.Types[n.Fun].IsType() {
+				break // conversion
+			}
+			if id, ok := unparen(n.Fun).(*ast.Ident); ok {
+				if b, ok := info.Uses[id].(*types.Builtin); ok && pureBuiltins[b.Name()] {
+					break
+				}
+			}
+			if sel, ok := unparen(n.Fun).(*ast.SelectorExpr); ok {
+				if b, ok := info.Uses[sel.Sel].(*types.Builtin); ok && pureBuiltins[b.Name()] {
+					break // unsafe.Sizeof, etc
+				}
+			}
+			pure = false
+		}
+		return pure
+	})
+	return pure
+}
+
+// A foldStep records the folding of one constant operation.
+type foldStep struct {
+	expr  string      // the operation, with the values of its operands substituted
+	value exact.Value // its value
+}
+
+// foldingSteps returns, in evaluation order, the folding steps of the
+// operations (unary, binary, conversions and calls of built-ins)
+// within the constant expression e. Named constants and literals are
+// not operations; literals appear as written and the values of named
+// constants are substituted into the steps that use them. The result
+// is nil if e is not constant or is folded in a single step, since
+// that step would merely restate e and its value.
+//
+func foldingSteps(info *loader.PackageInfo, e ast.Expr) []foldStep {
+	var steps []foldStep
+	// fold records the steps of e and returns the string form of its value.
+	var fold func(e ast.Expr) string
+	fold = func(e ast.Expr) string {
+		v := info.Types[e].Value
+		if v == nil {
+			return types.ExprString(e) // e.g. array operand of len
+		}
+		var step string
+		switch e := e.(type) {
+		case *ast.ParenExpr:
+			return fold(e.X)
+		case *ast.UnaryExpr:
+			step = e.Op.String() + fold(e.X)
+		case *ast.BinaryExpr:
+			x := fold(e.X)
+			y := fold(e.Y)
+			if isComplex(info.Types[e.X].Value) {
+				x = "(" + x + ")"
+			}
+			if isComplex(info.Types[e.Y].Value) {
+				y = "(" + y + ")"
+			}
+			step = fmt.Sprintf("%s %s %s", x, e.Op, y)
+		case *ast.CallExpr:
+			var args []string
+			for _, arg := range e.Args {
+				args = append(args, fold(arg))
+			}
+			step = fmt.Sprintf("%s(%s)", types.ExprString(e.Fun), strings.Join(args, ", "))
+		case *ast.BasicLit:
+			return e.Value
+		default:
+			return constString(v) // named constant
+		}
+		steps = append(steps, foldStep{step, v})
+		return constString(v)
+	}
+	if info.Types[e].Value != nil {
+		fold(e)
+	}
+	if len(steps) < 2 {
+		return nil
+	}
+	return steps
+}
+
+// isComplex reports whether the constant value v is a complex number
+// whose string form needs parentheses as an operand, e.g. 1 + 2i.
+func isComplex(v exact.Value) bool {
+	return v != nil && v.Kind() == exact.Complex && exact.Sign(exact.Real(v)) != 0
+}
+
+// pureBuiltins is the set of built-in functions, including those of
+// package unsafe, that have no side effects.
+var pureBuiltins = map[string]bool{
+	"Alignof":  true,
+	"Offsetof": true,
+	"Sizeof":   true,
+	"cap":      true,
+	"complex":  true,
+	"imag":     true,
+	"len":      true,
+	"real":     true,
+}
+
+// concreteMethod returns the method with a concrete receiver called
+// by the call expression expr, or nil if there is none.
+func concreteMethod(qpos *QueryPos, expr ast.Expr) *types.Func {
+	call, ok := expr.(*ast.CallExpr)
+	if !ok {
+		return nil
+	}
+	sel, ok := unparen(call.Fun).(*ast.SelectorExpr)
+	if !ok {
+		return nil
+	}
+	if s := qpos.info.Selections[sel]; s != nil && s.Kind() == types.MethodVal {
+		if fn := s.Obj().(*types.Func); funcKind(fn) == "method" {
+			return fn
+		}
+	}
+	return nil
+}
+
+// callStmt returns "defer" or "go" if path[0] is the call
+// expression of a defer or go statement, or "" otherwise.
+func callStmt(path []ast.Node) string {
+	if len(path) < 2 {
+		return ""
+	}
+	call, ok := path[0].(*ast.CallExpr)
+	if !ok {
+		return ""
+	}
+	switch stmt := path[1].(type) {
+	case *ast.DeferStmt:
+		if stmt.Call == call {
+			return "defer"
+		}
+	case *ast.GoStmt:
+		if stmt.Call == call {
+			return "go"
+		}
+	}
+	return ""
+}
+
+// initInfo describes the initialization of a package-level variable.
+type initInfo struct {
+	step, steps int          // 1-based position of its initializer in Info.InitOrder, and its length
+	deps        []*types.Var // package-level variables on which the initializer depends
+}
+
+// initDependencies returns the initialization information for the
+// package-level variable v, or nil if v has no initializer.
+//
+// Following the spec, the initializer depends on each package-level
+// variable to which it refers, directly or through the bodies of the
+// package's functions and methods to which it refers.  Dependencies
+// are listed in initialization order; those without initializers,
+// which are merely zeroed, come first.
+//
+func initDependencies(info *loader.PackageInfo, v *types.Var) *initInfo {
+	var rhs ast.Expr
+	step := make(map[*types.Var]int)
+	for i, init := range info.InitOrder {
+		for _, lhs := range init.Lhs {
+			step[lhs] = i + 1
+			if lhs == v {
+				rhs = init.Rhs
+			}
+		}
+	}
+	if rhs == nil {
+		return nil // no initializer
+	}
+
+	decls := make(map[*types.Func]*ast.FuncDecl)
+	for _, f := range info.Files {
+		for _, decl := range f.Decls {
+			if decl, ok := decl.(*ast.FuncDecl); ok && decl.Body != nil {
+				if fn, ok := info.Defs[decl.Name].(*types.Func); ok {
+					decls[fn] = decl
+				}
+			}
+		}
+	}
+
+	pkg := info.Pkg
+	seen := make(map[types.Object]bool)
+	var deps []*types.Var
+	var visit func(n ast.Node) bool
+	visit = func(n ast.Node) bool {
+		id, ok := n.(*ast.Ident)
+		if !ok {
+			return true
+		}
+		switch obj := info.Uses[id].(type) {
+		case *types.Var:
+			if obj.Pkg() == pkg && obj.Parent() == pkg.Scope() && !seen[obj] {
+				seen[obj] = true
+				deps = append(deps, obj)
+			}
+		case *types.Func:
+			if decl := decls[obj]; decl != nil && !seen[obj] {
+				seen[obj] = true
+				ast.Inspect(decl.Body, visit)
+			}
+		}
+		return true
+	}
+	ast.Inspect(rhs, visit)
+	sort.Sort(byInitOrder{deps, step})
+
+	return &initInfo{step: step[v], steps: len(info.InitOrder), deps: deps}
+}
+
+type byInitOrder struct {
+	vars []*types.Var
+	step map[*types.Var]int
+}
+
+func (s byInitOrder) Len() int      { return len(s.vars) }
+func (s byInitOrder) Swap(i, j int) { s.vars[i], s.vars[j] = s.vars[j], s.vars[i] }
+func (s byInitOrder) Less(i, j int) bool {
+	x, y := s.vars[i], s.vars[j]
+	if s.step[x] != s.step[y] {
+		return s.step[x] < s.step[y]
+	}
+	return x.Pos() < y.Pos()
+}
+
+// isInterfaceMethod reports whether fn is an abstract method.
+func isInterfaceMethod(fn *types.Func) bool {
+	recv := fn.Type().(*types.Signature).Recv()
+	return recv != nil && isInterface(recv.Type())
+}
+
+// implementations returns the concrete methods that implement the
+// interface method m, at most one for each named type T (or *T, if T
+// does not suffice) whose method set satisfies m's interface.
+//
+// The named types are drawn from the query package only; the
+// 'implements' mode covers the whole program.
+//
+func implementations(qpos *QueryPos, m *types.Func) []*types.Selection {
+	iface := m.Type().(*types.Signature).Recv().Type().Underlying().(*types.Interface)
+
+	var msets types.MethodSetCache
+	var impls []*types.Selection
+	for _, obj := range qpos.info.Defs {
+		tname, ok := obj.(*types.TypeName)
+		if !ok || isInterface(tname.Type()) {
+			continue
+		}
+		T := tname.Type()
+		if !types.AssignableTo(T, iface) {
+			T = types.NewPointer(T)
+			if !types.AssignableTo(T, iface) {
+				continue
+			}
+		}
+		if sel := msets.MethodSet(T).Lookup(m.Pkg(), m.Name()); sel != nil {
+			impls = append(impls, sel)
+		}
+	}
+	sort.Sort(byRecvString(impls)) // to ensure determinism
+	return impls
+}
+
+// interfacesWithMethod returns the named interface types having a
+// method with the same name (and package, if unexported) and
+// signature as the concrete method m, and to whose satisfaction m may
+// therefore contribute.
+//
+// The interfaces are drawn from the query package only; the
+// 'implements' mode covers the whole program.
+//
+func interfacesWithMethod(qpos *QueryPos, m *types.Func) []*types.TypeName {
+	var ifaces []*types.TypeName
+	for _, obj := range qpos.info.Defs {
+		tname, ok := obj.(*types.TypeName)
+		if !ok || !isInterface(tname.Type()) {
+			continue
+		}
+		obj, _, _ := types.LookupFieldOrMethod(tname.Type(), false, m.Pkg(), m.Name())
+		if meth, ok := obj.(*types.Func); ok && types.Identical(meth.Type(), m.Type()) {
+			ifaces = append(ifaces, tname)
+		}
+	}
+	sort.Sort(byTypeNameString(ifaces)) // to ensure determinism
+	return ifaces
+}
+
+type byTypeNameString []*types.TypeName
+
+func (s byTypeNameString) Len() int           { return len(s) }
+func (s byTypeNameString) Less(i, j int) bool { return s[i].Type().String() < s[j].Type().String() }
+func (s byTypeNameString) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+type byRecvString []*types.Selection
+
+func (s byRecvString) Len() int           { return len(s) }
+func (s byRecvString) Less(i, j int) bool { return s[i].Recv().String() < s[j].Recv().String() }
+func (s byRecvString) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
 // ---- TYPE ------------------------------------------------------------
 
 func describeType(o *Oracle, qpos *QueryPos, path []ast.Node) (*describeTypeResult, error) {
@@ -424,12 +1048,15 @@ func describeType(o *Oracle, qpos *QueryPos, path []ast.Node) (*describeTypeResu
 
 	description = description + "type " + qpos.TypeString(t)
 
+	var deprecated string
+	if nt, ok := t.(*types.Named); ok {
+		deprecated = deprecation(o, qpos, nt.Obj())
+	}
+
 	// Show sizes for structs and named types (it's fairly obvious for others).
 	switch t.(type) {
 	case *types.Named, *types.Struct:
-		// TODO(adonovan): use o.imp.Config().TypeChecker.Sizes when
-		// we add the Config() method (needs some thought).
-		szs := types.StdSizes{8, 8}
+		szs := o.sizes()
 		description = fmt.Sprintf("%s (size %d, align %d)", description,
 			szs.Sizeof(t), szs.Alignof(t))
 	}
@@ -440,15 +1067,287 @@ func describeType(o *Oracle, qpos *QueryPos, path []ast.Node) (*describeTypeResu
 		description: description,
 		typ:         t,
 		methods:     accessibleMethods(t, qpos.info.Pkg),
+		ambiguous:   ambiguousMethods(t, qpos.info.Pkg),
+		isError:     types.Implements(t, errorType),
+		qualified:   localTypeName(qpos, t),
+		platform:    o.platform(),
+		sizes:       o.sizes(),
+		zeroSize:    o.sizes().Sizeof(t) == 0,
+		deprecated:  deprecated,
+		api:         exportedUses(qpos.info.Pkg, t),
 	}, nil
 }
 
+// exportedUses returns the exported package-level functions, and
+// exported methods of exported types, of package pkg whose parameter
+// or result types mention type t, ordered by the name of the function
+// or of the method's receiver type.
+func exportedUses(pkg *types.Package, t types.Type) []*types.Func {
+	var fns []*types.Func
+	check := func(fn *types.Func) {
+		sig := fn.Type().(*types.Signature)
+		if tupleMentions(sig.Params(), t) || tupleMentions(sig.Results(), t) {
+			fns = append(fns, fn)
+		}
+	}
+	scope := pkg.Scope()
+	for _, name := range scope.Names() {
+		if !ast.IsExported(name) {
+			continue
+		}
+		switch obj := scope.Lookup(name).(type) {
+		case *types.Func:
+			check(obj)
+		case *types.TypeName:
+			if nt, ok := obj.Type().(*types.Named); ok {
+				for i := 0; i < nt.NumMethods(); i++ {
+					if m := nt.Method(i); m.Exported() {
+						check(m)
+					}
+				}
+			}
+		}
+	}
+	return fns
+}
+
+// tupleMentions reports whether the type of any variable in tuple
+// mentions type t.
+func tupleMentions(tuple *types.Tuple, t types.Type) bool {
+	for i := 0; i < tuple.Len(); i++ {
+		if typeMentions(tuple.At(i).Type(), t) {
+			return true
+		}
+	}
+	return false
+}
+
+// typeMentions reports whether type T is t or is composed from it,
+// e.g. *t, []t or map[string]t.  Named types other than t are not
+// expanded.
+func typeMentions(T, t types.Type) bool {
+	if types.Identical(T, t) {
+		return true
+	}
+	switch T := T.(type) {
+	case *types.Pointer:
+		return typeMentions(T.Elem(), t)
+	case *types.Slice:
+		return typeMentions(T.Elem(), t)
+	case *types.Array:
+		return typeMentions(T.Elem(), t)
+	case *types.Chan:
+		return typeMentions(T.Elem(), t)
+	case *types.Map:
+		return typeMentions(T.Key(), t) || typeMentions(T.Elem(), t)
+	case *types.Signature:
+		return tupleMentions(T.Params(), t) || tupleMentions(T.Results(), t)
+	case *types.Struct:
+		for i := 0; i < T.NumFields(); i++ {
+			if typeMentions(T.Field(i).Type(), t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// localTypeName returns the fully qualified name of t if it is a
+// named type declared within a function of the query package,
+// e.g. "pkg.(*T).f.U", or "" otherwise.  Types declared within a
+// function literal are qualified by the enclosing declared function.
+func localTypeName(qpos *QueryPos, t types.Type) string {
+	nt, ok := t.(*types.Named)
+	if !ok {
+		return ""
+	}
+	obj := nt.Obj()
+	if obj.Pkg() != qpos.info.Pkg || obj.Parent() == obj.Pkg().Scope() {
+		return "" // not local to a function of the query package
+	}
+	for _, f := range qpos.info.Files {
+		if f.Pos() > obj.Pos() || obj.Pos() >= f.End() {
+			continue
+		}
+		path, _ := astutil.PathEnclosingInterval(f, obj.Pos(), obj.Pos())
+		for _, n := range path {
+			if decl, ok := n.(*ast.FuncDecl); ok {
+				if fn, ok := qpos.info.Defs[decl.Name].(*types.Func); ok {
+					return fn.FullName() + "." + obj.Name()
+				}
+				return "" // e.g. redeclared function
+			}
+		}
+	}
+	return ""
+}
+
+// deprecation returns the deprecation notice in the doc comment of
+// the declaration of obj, that is, the text of the paragraph that
+// begins "Deprecated: ", or "" if there is none.
+//
+// The notice is found only if the declaring package's ASTs are
+// available, either because it is the query package or because the
+// Oracle retains type information, and were parsed with comments.
+//
+func deprecation(o *Oracle, qpos *QueryPos, obj types.Object) string {
+	pos := obj.Pos()
+	if !pos.IsValid() {
+		return "" // e.g. built-in, or package loaded from export data
+	}
+	info := qpos.info
+	if obj.Pkg() != info.Pkg {
+		if info = o.typeInfo[obj.Pkg()]; info == nil {
+			return ""
+		}
+	}
+	var f *ast.File
+	for _, file := range info.Files {
+		if file.Pos() <= pos && pos < file.End() {
+			f = file
+			break
+		}
+	}
+	if f == nil {
+		return ""
+	}
+
+	path, _ := astutil.PathEnclosingInterval(f, pos, pos)
+	var doc *ast.CommentGroup
+outer:
+	for _, n := range path {
+		switch n := n.(type) {
+		case *ast.Field:
+			doc = n.Doc
+			break outer
+		case *ast.FuncDecl:
+			doc = n.Doc
+			break outer
+		case *ast.ValueSpec:
+			doc = n.Doc
+		case *ast.TypeSpec:
+			doc = n.Doc
+		case *ast.GenDecl:
+			if doc == nil && len(n.Specs) == 1 {
+				doc = n.Doc
+			}
+			break outer
+		}
+	}
+	if doc == nil {
+		return ""
+	}
+	for _, para := range strings.Split(doc.Text(), "\n\n") {
+		if strings.HasPrefix(para, "Deprecated: ") {
+			return strings.Join(strings.Fields(para[len("Deprecated: "):]), " ")
+		}
+	}
+	return ""
+}
+
 type describeTypeResult struct {
 	qpos        *QueryPos
 	node        ast.Node
 	description string
 	typ         types.Type
 	methods     []*types.Selection
+	ambiguous   []string // names of methods not promoted due to ambiguity
+	isError     bool     // type implements the error interface
+	qualified   string   // fully qualified name of a function-local type
+	platform    string   // GOOS/GOARCH assumed for sizes
+	sizes       types.Sizes
+	zeroSize    bool          // values of the type occupy no memory
+	deprecated  string        // deprecation notice of a named type, if any
+	api         []*types.Func // exported functions and methods of the package whose signatures mention the type
+}
+
+// cacheLineSize is the cache line size, in bytes, assumed when
+// reporting struct fields that straddle a cache line boundary.
+const cacheLineSize = 64
+
+// fieldLayout returns the offset and size of each field of struct s.
+func fieldLayout(sizes types.Sizes, s *types.Struct) (offsets, sizeofs []int64) {
+	fields := make([]*types.Var, s.NumFields())
+	for i := range fields {
+		fields[i] = s.Field(i)
+	}
+	offsets = sizes.Offsetsof(fields)
+	for _, f := range fields {
+		sizeofs = append(sizeofs, sizes.Sizeof(f.Type()))
+	}
+	return
+}
+
+// straddlesCacheLine reports whether the bytes [offset, offset+size)
+// span a cache line boundary.
+func straddlesCacheLine(offset, size int64) bool {
+	return size > 0 && offset/cacheLineSize != (offset+size-1)/cacheLineSize
+}
+
+// basicConvTypes are the types whose convertibility to and from a
+// described type is reported: the predeclared types, plus the byte
+// and rune slices that may be converted to and from strings.
+var basicConvTypes = []types.Type{
+	types.Typ[types.Bool],
+	types.Typ[types.Int],
+	types.Typ[types.Int8],
+	types.Typ[types.Int16],
+	types.Typ[types.Int32],
+	types.Typ[types.Int64],
+	types.Typ[types.Uint],
+	types.Typ[types.Uint8],
+	types.Typ[types.Uint16],
+	types.Typ[types.Uint32],
+	types.Typ[types.Uint64],
+	types.Typ[types.Uintptr],
+	types.Typ[types.Float32],
+	types.Typ[types.Float64],
+	types.Typ[types.Complex64],
+	types.Typ[types.Complex128],
+	types.Typ[types.String],
+	types.Typ[types.UnsafePointer],
+	types.NewSlice(types.Universe.Lookup("byte").Type()),
+	types.NewSlice(types.Universe.Lookup("rune").Type()),
+}
+
+// convertibleTypes returns the names of the types among
+// basicConvTypes, other than T itself, to which and from which
+// values of type T may be converted.
+func convertibleTypes(qpos *QueryPos, T types.Type) (to, from []string) {
+	for _, U := range basicConvTypes {
+		if types.Identical(T, U) {
+			continue
+		}
+		if types.ConvertibleTo(T, U) {
+			to = append(to, qpos.TypeString(U))
+		}
+		if types.ConvertibleTo(U, T) {
+			from = append(from, qpos.TypeString(U))
+		}
+	}
+	return
+}
+
+// errorType is the underlying interface of the built-in error type.
+var errorType = types.Universe.Lookup("error").Type().Underlying().(*types.Interface)
+
+// readerType and writerType are the underlying interfaces of
+// io.Reader and io.Writer, constructed so that they are available
+// even if package io is not part of the program.
+var (
+	readerType = ioMethodInterface("Read")
+	writerType = ioMethodInterface("Write")
+)
+
+// ioMethodInterface returns the interface type whose sole method has
+// the specified name and the signature func(p []byte) (n int, err error).
+func ioMethodInterface(name string) *types.Interface {
+	params := types.NewTuple(types.NewParam(token.NoPos, nil, "p", types.NewSlice(types.Typ[types.Byte])))
+	results := types.NewTuple(
+		types.NewParam(token.NoPos, nil, "n", types.Typ[types.Int]),
+		types.NewParam(token.NoPos, nil, "err", types.Universe.Lookup("error").Type()))
+	sig := types.NewSignature(nil, nil, params, results, false)
+	return types.NewInterface([]*types.Func{types.NewFunc(token.NoPos, nil, name, sig)}, nil).Complete()
 }
 
 func (r *describeTypeResult) display(printf printfFunc) {
@@ -471,23 +1370,82 @@ func (r *describeTypeResult) display(printf printfFunc) {
 			printf(r.node, "No methods.")
 		}
 	}
+
+	if len(r.ambiguous) > 0 {
+		printf(r.node, "Ambiguous methods (not promoted):")
+		for _, name := range r.ambiguous {
+			printf(r.node, "\t%s", name)
+		}
+	}
+
+	// Flag struct fields that straddle a cache line.
+	if st, ok := r.typ.Underlying().(*types.Struct); ok {
+		offsets, sizeofs := fieldLayout(r.sizes, st)
+		for i := 0; i < st.NumFields(); i++ {
+			if straddlesCacheLine(offsets[i], sizeofs[i]) {
+				printf(st.Field(i), "field %s (offset %d, size %d) straddles a %d-byte cache line",
+					st.Field(i).Name(), offsets[i], sizeofs[i], cacheLineSize)
+			}
+		}
+	}
+
+	if r.zeroSize {
+		printf(r.node, "Zero-size type: distinct variables may have the same address.")
+	}
+
+	if r.deprecated != "" {
+		printf(r.node, "Deprecated: %s", r.deprecated)
+	}
+
+	if r.isError {
+		printf(r.node, "Implements error.")
+	}
+
+	if len(r.api) > 0 {
+		printf(r.node, "Used in the signatures of these %d exported functions:", len(r.api))
+		for _, fn := range r.api {
+			printf(fn, "\t%s", r.qpos.ObjectString(fn))
+		}
+	}
+
+	if r.qualified != "" {
+		printf(r.node, "Local type %s", r.qualified)
+	}
 }
 
 func (r *describeTypeResult) toSerial(res *serial.Result, fset *token.FileSet) {
+	convTo, convFrom := convertibleTypes(r.qpos, r.typ)
 	var namePos, nameDef string
 	if nt, ok := r.typ.(*types.Named); ok {
 		namePos = fset.Position(nt.Obj().Pos()).String()
 		nameDef = nt.Underlying().String()
 	}
+	var api []serial.DescribeMethod
+	for _, fn := range r.api {
+		api = append(api, serial.DescribeMethod{
+			Name: fn.FullName(),
+			Pos:  fset.Position(fn.Pos()).String(),
+		})
+	}
 	res.Describe = &serial.Describe{
 		Desc:   r.description,
 		Pos:    fset.Position(r.node.Pos()).String(),
 		Detail: "type",
 		Type: &serial.DescribeType{
-			Type:    r.qpos.TypeString(r.typ),
-			NamePos: namePos,
-			NameDef: nameDef,
-			Methods: methodsToSerial(r.qpos.info.Pkg, r.methods, fset),
+			Type:       r.qpos.TypeString(r.typ),
+			NamePos:    namePos,
+			NameDef:    nameDef,
+			Methods:    methodsToSerial(r.qpos.info.Pkg, r.methods, fset),
+			Fields:     fieldsToSerial(r.qpos, r.typ, r.sizes, fset),
+			ConvTo:     convTo,
+			ConvFrom:   convFrom,
+			Ambiguous:  r.ambiguous,
+			IsError:    r.isError,
+			ZeroSize:   r.zeroSize,
+			API:        api,
+			Deprecated: r.deprecated,
+			Qualified:  r.qualified,
+			Platform:   r.platform,
 		},
 	}
 }
@@ -497,6 +1455,7 @@ func (r *describeTypeResult) toSerial(res *serial.Result, fset *token.FileSet) {
 func describePackage(o *Oracle, qpos *QueryPos, path []ast.Node) (*describePackageResult, error) {
 	var description string
 	var pkg *types.Package
+	var blank bool // blank import, for side effects only
 	switch n := path[0].(type) {
 	case *ast.ImportSpec:
 		var pkgname *types.PkgName
@@ -506,6 +1465,7 @@ func describePackage(o *Oracle, qpos *QueryPos, path []ast.Node) (*describePacka
 			pkgname = p.(*types.PkgName)
 		}
 		pkg = pkgname.Imported()
+		blank = pkgname.Name() == "_"
 		description = fmt.Sprintf("import of package %q", pkg.Path())
 
 	case *ast.Ident:
@@ -516,7 +1476,9 @@ func describePackage(o *Oracle, qpos *QueryPos, path []ast.Node) (*describePacka
 		} else {
 			// e.g. import id "..."
 			//  or  id.F()
-			pkg = qpos.info.ObjectOf(n).(*types.PkgName).Imported()
+			pkgname := qpos.info.ObjectOf(n).(*types.PkgName)
+			pkg = pkgname.Imported()
+			blank = pkgname.Name() == "_"
 			description = fmt.Sprintf("reference to package %q", pkg.Path())
 		}
 
@@ -525,9 +1487,15 @@ func describePackage(o *Oracle, qpos *QueryPos, path []ast.Node) (*describePacka
 		return nil, fmt.Errorf("unexpected AST for package: %T", n)
 	}
 
+	// A blank import makes no members accessible; it is
+	// for the side effects of the package's initialization.
+	if blank {
+		description = fmt.Sprintf("blank import of package %q, for its side effects only", pkg.Path())
+	}
+
 	var members []*describeMember
 	// NB: "unsafe" has no types.Package
-	if pkg != nil {
+	if pkg != nil && !blank {
 		// Enumerate the accessible package members
 		// in lexicographic order.
 		for _, name := range pkg.Scope().Names() {
@@ -546,7 +1514,7 @@ func describePackage(o *Oracle, qpos *QueryPos, path []ast.Node) (*describePacka
 		}
 	}
 
-	return &describePackageResult{o.fset, path[0], description, pkg, members}, nil
+	return &describePackageResult{o.fset, path[0], description, pkg, members, blank}, nil
 }
 
 type describePackageResult struct {
@@ -554,7 +1522,8 @@ type describePackageResult struct {
 	node        ast.Node
 	description string
 	pkg         *types.Package
-	members     []*describeMember // in lexicographic name order
+	members     []*describeMember // in lexicographic name order, unless blank
+	blank       bool              // blank import, for side effects only
 }
 
 type describeMember struct {
@@ -643,6 +1612,7 @@ func (r *describePackageResult) toSerial(res *serial.Result, fset *token.FileSet
 		Package: &serial.DescribePackage{
 			Path:    r.pkg.Path(),
 			Members: members,
+			Blank:   r.blank,
 		},
 	}
 }
@@ -679,24 +1649,67 @@ func describeStmt(o *Oracle, qpos *QueryPos, path []ast.Node) (*describeStmtResu
 		// Nothing much to say about statements.
 		description = astutil.NodeDescription(n)
 	}
-	return &describeStmtResult{o.fset, path[0], description}, nil
+	return &describeStmtResult{o.fset, path[0], description, enclosingBreakable(path)}, nil
+}
+
+// enclosingBreakable returns the innermost for, range, switch, type
+// switch or select statement strictly enclosing path[0] within the
+// same function, or nil if there is none. It is the default target
+// of an unlabelled break (and, for loops, continue) at path[0].
+func enclosingBreakable(path []ast.Node) ast.Stmt {
+	for _, n := range path[1:] {
+		switch n := n.(type) {
+		case *ast.ForStmt, *ast.RangeStmt, *ast.SwitchStmt, *ast.TypeSwitchStmt, *ast.SelectStmt:
+			return n.(ast.Stmt)
+		case *ast.FuncLit, *ast.FuncDecl:
+			return nil
+		}
+	}
+	return nil
 }
 
 type describeStmtResult struct {
 	fset        *token.FileSet
 	node        ast.Node
 	description string
+	enclosing   ast.Stmt // innermost enclosing loop, switch or select, or nil
+}
+
+// isLoop reports whether s is a for or range statement.
+func isLoop(s ast.Stmt) bool {
+	switch s.(type) {
+	case *ast.ForStmt, *ast.RangeStmt:
+		return true
+	}
+	return false
 }
 
 func (r *describeStmtResult) display(printf printfFunc) {
 	printf(r.node, "%s", r.description)
+	if r.enclosing != nil {
+		targets := "break"
+		if isLoop(r.enclosing) {
+			targets = "break and continue"
+		}
+		printf(r.enclosing, "innermost enclosing %s (default target of %s)",
+			astutil.NodeDescription(r.enclosing), targets)
+	}
 }
 
 func (r *describeStmtResult) toSerial(res *serial.Result, fset *token.FileSet) {
+	var enclosing *serial.DescribeEnclosing
+	if r.enclosing != nil {
+		enclosing = &serial.DescribeEnclosing{
+			Desc: astutil.NodeDescription(r.enclosing),
+			Pos:  fset.Position(r.enclosing.Pos()).String(),
+			Loop: isLoop(r.enclosing),
+		}
+	}
 	res.Describe = &serial.Describe{
-		Desc:   r.description,
-		Pos:    fset.Position(r.node.Pos()).String(),
-		Detail: "unknown",
+		Desc:      r.description,
+		Pos:       fset.Position(r.node.Pos()).String(),
+		Detail:    "unknown",
+		Enclosing: enclosing,
 	}
 }
 
@@ -727,6 +1740,39 @@ func accessibleMethods(t types.Type, from *types.Package) []*types.Selection {
 	return methods
 }
 
+// ambiguousMethods returns the sorted names of the methods of the
+// embedded fields of struct type t (or *t) that are not promoted to
+// t because the same name occurs more than once at the shallowest
+// embedding depth at which it is found.
+//
+func ambiguousMethods(t types.Type, from *types.Package) []string {
+	s, ok := deref(t).Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+	var names []string
+	seen := make(map[string]bool)
+	for i := 0; i < s.NumFields(); i++ {
+		f := s.Field(i)
+		if !f.Anonymous() {
+			continue
+		}
+		for _, meth := range typeutil.IntuitiveMethodSet(deref(f.Type()), nil) {
+			name := meth.Obj().Name()
+			if seen[name] || !isAccessibleFrom(meth.Obj(), from) {
+				continue
+			}
+			seen[name] = true
+			obj, index, _ := types.LookupFieldOrMethod(t, true, from, name)
+			if obj == nil && index != nil {
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
 func isAccessibleFrom(obj types.Object, pkg *types.Package) bool {
 	return ast.IsExported(obj.Name()) || obj.Pkg() == pkg
 }
@@ -741,3 +1787,28 @@ func methodsToSerial(this *types.Package, methods []*types.Selection, fset *toke
 	}
 	return jmethods
 }
+
+// fieldsToSerial returns the fields of T, in declaration order, if
+// its underlying type is a struct.
+func fieldsToSerial(qpos *QueryPos, T types.Type, sizes types.Sizes, fset *token.FileSet) []serial.DescribeField {
+	s, ok := T.Underlying().(*types.Struct)
+	if !ok {
+		return nil
+	}
+	offsets, sizeofs := fieldLayout(sizes, s)
+	var fields []serial.DescribeField
+	for i := 0; i < s.NumFields(); i++ {
+		f := s.Field(i)
+		fields = append(fields, serial.DescribeField{
+			Name:      f.Name(),
+			Type:      qpos.TypeString(f.Type()),
+			Tag:       s.Tag(i),
+			Embedded:  f.Anonymous(),
+			Offset:    offsets[i],
+			Size:      sizeofs[i],
+			Straddles: straddlesCacheLine(offsets[i], sizeofs[i]),
+			Pos:       fset.Position(f.Pos()).String(),
+		})
+	}
+	return fields
+}
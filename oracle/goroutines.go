@@ -0,0 +1,110 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package oracle
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+
+	"code.google.com/p/go.tools/go/callgraph"
+	"code.google.com/p/go.tools/go/ssa"
+	"code.google.com/p/go.tools/oracle/serial"
+)
+
+// Goroutines reports the go statements that may be executed, directly
+// or transitively, by a call to the function immediately enclosing
+// the specified source location, according to the call graph.
+//
+func goroutines(o *Oracle, qpos *QueryPos) (queryResult, error) {
+	pkg := o.prog.Package(qpos.info.Pkg)
+	if pkg == nil {
+		return nil, fmt.Errorf("no SSA package")
+	}
+	if !ssa.HasEnclosingFunction(pkg, qpos.path) {
+		return nil, fmt.Errorf("this position is not inside a function")
+	}
+
+	buildSSA(o)
+
+	target := ssa.EnclosingFunction(pkg, qpos.path)
+	if target == nil {
+		return nil, fmt.Errorf("no SSA function built for this location (dead code?)")
+	}
+
+	cg, err := callGraph(o)
+	if err != nil {
+		return nil, err
+	}
+
+	// Breadth-first search of the callees of target.
+	// If target is unreachable, only its own body is inspected.
+	funcs := []*ssa.Function{target}
+	if n := cg.Nodes[target]; n != nil {
+		funcs = funcs[:0]
+		seen := map[*callgraph.Node]bool{n: true}
+		for queue := []*callgraph.Node{n}; len(queue) > 0; queue = queue[1:] {
+			n := queue[0]
+			funcs = append(funcs, n.Func)
+			for _, e := range n.Out {
+				if !seen[e.Callee] {
+					seen[e.Callee] = true
+					queue = append(queue, e.Callee)
+				}
+			}
+		}
+	}
+
+	var sites []*ssa.Go
+	for _, fn := range funcs {
+		for _, b := range fn.Blocks {
+			for _, instr := range b.Instrs {
+				if g, ok := instr.(*ssa.Go); ok {
+					sites = append(sites, g)
+				}
+			}
+		}
+	}
+	sort.Sort(byGoPos(sites))
+
+	return &goroutinesResult{
+		target: target,
+		sites:  sites,
+	}, nil
+}
+
+type byGoPos []*ssa.Go
+
+func (s byGoPos) Len() int           { return len(s) }
+func (s byGoPos) Less(i, j int) bool { return s[i].Pos() < s[j].Pos() }
+func (s byGoPos) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+type goroutinesResult struct {
+	target *ssa.Function
+	sites  []*ssa.Go // go statements reachable from target, in order of position
+}
+
+func (r *goroutinesResult) display(printf printfFunc) {
+	if r.sites == nil {
+		printf(r.target, "%s does not spawn goroutines", r.target)
+	} else {
+		printf(r.target, "%s may spawn goroutines at these %d sites:", r.target, len(r.sites))
+		for _, g := range r.sites {
+			printf(g, "\tgo statement in %s", g.Parent())
+		}
+	}
+}
+
+func (r *goroutinesResult) toSerial(res *serial.Result, fset *token.FileSet) {
+	var sites []string
+	for _, g := range r.sites {
+		sites = append(sites, fset.Position(g.Pos()).String())
+	}
+	res.Goroutines = &serial.Goroutines{
+		Pos:    fset.Position(r.target.Pos()).String(),
+		Target: r.target.String(),
+		Sites:  sites,
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package oracle
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"sort"
+
+	"code.google.com/p/go.tools/go/types"
+	"code.google.com/p/go.tools/oracle/serial"
+)
+
+// Mapkey reports the map types, written anywhere in the program, whose
+// key type is identical to the selected type.
+//
+func mapkey(o *Oracle, qpos *QueryPos) (queryResult, error) {
+	path, action := findInterestingNode(qpos.info, qpos.path)
+	if action != actionType {
+		return nil, fmt.Errorf("no type here")
+	}
+	T := qpos.info.TypeOf(path[0].(ast.Expr))
+	if T == nil {
+		return nil, fmt.Errorf("no type here")
+	}
+
+	var maps []*ast.MapType
+	for _, info := range o.typeInfo {
+		for expr, tv := range info.Types {
+			if mt, ok := expr.(*ast.MapType); ok {
+				if m, ok := tv.Type.(*types.Map); ok && types.Identical(m.Key(), T) {
+					maps = append(maps, mt)
+				}
+			}
+		}
+	}
+	sort.Sort(byMapPos(maps)) // to ensure determinism
+
+	var pos interface{} = qpos
+	if nt, ok := T.(*types.Named); ok {
+		pos = nt.Obj()
+	}
+
+	return &mapkeyResult{
+		qpos: qpos,
+		t:    T,
+		pos:  pos,
+		maps: maps,
+	}, nil
+}
+
+type byMapPos []*ast.MapType
+
+func (s byMapPos) Len() int           { return len(s) }
+func (s byMapPos) Less(i, j int) bool { return s[i].Pos() < s[j].Pos() }
+func (s byMapPos) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+type mapkeyResult struct {
+	qpos *QueryPos
+	t    types.Type     // queried type
+	pos  interface{}    // pos of t (*types.TypeName or *QueryPos)
+	maps []*ast.MapType // map types keyed by t, in order of position
+}
+
+func (r *mapkeyResult) display(printf printfFunc) {
+	T := r.qpos.TypeString(r.t)
+	if r.maps == nil {
+		printf(r.pos, "%s is not used as a map key type", T)
+	} else {
+		printf(r.pos, "%s is used as the key type of these %d map types:", T, len(r.maps))
+		for _, mt := range r.maps {
+			printf(mt, "\t%s", types.ExprString(mt))
+		}
+	}
+}
+
+func (r *mapkeyResult) toSerial(res *serial.Result, fset *token.FileSet) {
+	var maps []string
+	for _, mt := range r.maps {
+		maps = append(maps, fset.Position(mt.Pos()).String())
+	}
+	res.MapKey = &serial.MapKey{
+		Type: r.qpos.TypeString(r.t),
+		Maps: maps,
+	}
+}
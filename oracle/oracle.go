@@ -53,10 +53,15 @@ import (
 	"fmt"
 	"go/ast"
 	"go/build"
+	"go/parser"
 	"go/token"
 	"io"
+	"strconv"
+	"strings"
 
 	"code.google.com/p/go.tools/astutil"
+	"code.google.com/p/go.tools/go/callgraph"
+	"code.google.com/p/go.tools/go/exact"
 	"code.google.com/p/go.tools/go/loader"
 	"code.google.com/p/go.tools/go/pointer"
 	"code.google.com/p/go.tools/go/ssa"
@@ -70,6 +75,9 @@ type Oracle struct {
 	prog      *ssa.Program                           // the SSA program [needSSA]
 	ptaConfig pointer.Config                         // pointer analysis configuration [needPTA]
 	typeInfo  map[*types.Package]*loader.PackageInfo // type info for all ASTs in the program [needRetainTypeInfo]
+	build     *build.Context                         // loader's build context; nil => build.Default [describe]
+	typeSizes types.Sizes                            // sizes used by the type checker; nil => default [describe]
+	arg       string                                 // argument of the current query mode [needArg]
 }
 
 // A set of bits indicating the analytical requirements of each mode.
@@ -83,6 +91,7 @@ const (
 	needRetainTypeInfo             // needs to retain type info for all ASTs in the program
 	needSSA                        // needs ssa.Packages for whole program
 	needSSADebug                   // needs debug info for ssa.Packages
+	needArg                        // needs a mode argument, e.g. T in "convert=T"
 	needPTA            = needSSA   // needs pointer analysis
 	needAll            = -1        // needs everything (e.g. a sequence of queries)
 )
@@ -99,19 +108,47 @@ var modes = []*modeInfo{
 	{"callers", needPTA | needPos, callers},
 	{"callgraph", needPTA, doCallgraph},
 	{"callstack", needPTA | needPos, callstack},
+	{"goroutines", needPTA | needPos, goroutines},
 	{"peers", needPTA | needSSADebug | needPos, peers},
 	{"pointsto", needPTA | needSSADebug | needExactPos, pointsto},
+	{"recursion", needPTA | needPos, recursion},
+
+	// SSA-based analyses, whole program:
+	{"ssa", needSSA | needSSADebug | needExactPos, ssaQuery},
 
 	// Type-based, modular analyses:
+	{"convert", needArg | needExactPos, convert},
 	{"definition", needPos, definition},
 	{"describe", needExactPos, describe},
 	{"freevars", needPos, freevars},
 
 	// Type-based, whole-program analyses:
 	{"implements", needRetainTypeInfo | needPos, implements},
+	{"mapkey", needRetainTypeInfo | needPos, mapkey},
 	{"referrers", needRetainTypeInfo | needPos, referrers},
 }
 
+// splitMode splits a mode of the form "name=arg" into its name and
+// argument, e.g. "convert=[]byte".
+func splitMode(mode string) (name, arg string) {
+	if i := strings.Index(mode, "="); i >= 0 {
+		return mode[:i], mode[i+1:]
+	}
+	return mode, ""
+}
+
+// checkModeArg reports an error if the presence of the mode argument
+// arg does not match the needs of mode minfo.
+func checkModeArg(minfo *modeInfo, arg string) error {
+	if minfo.needs&needArg != 0 && arg == "" {
+		return fmt.Errorf("mode %q requires an argument: %s=...", minfo.name, minfo.name)
+	}
+	if minfo.needs&needArg == 0 && arg != "" {
+		return fmt.Errorf("mode %q takes no argument", minfo.name)
+	}
+	return nil
+}
+
 func findMode(mode string) *modeInfo {
 	for _, m := range modes {
 		if m.name == mode {
@@ -184,7 +221,8 @@ func (res *Result) Serial() *serial.Result {
 // Query runs a single oracle query.
 //
 // args specify the main package in (*loader.Config).FromArgs syntax.
-// mode is the query mode ("callers", etc).
+// mode is the query mode ("callers", etc), plus its argument, if any,
+// as in "convert=T".
 // ptalog is the (optional) pointer-analysis log file.
 // buildContext is the go/build configuration for locating packages.
 // reflection determines whether to model reflection soundly (currently slow).
@@ -217,12 +255,20 @@ func Query(args []string, mode, pos string, ptalog io.Writer, buildContext *buil
 		return what(pos, buildContext)
 	}
 
+	mode, arg := splitMode(mode)
 	minfo := findMode(mode)
 	if minfo == nil {
 		return nil, fmt.Errorf("invalid mode type: %q", mode)
 	}
+	if err := checkModeArg(minfo, arg); err != nil {
+		return nil, err
+	}
 
 	conf := loader.Config{Build: buildContext, SourceImports: true}
+	conf.TypeChecker.Sizes = targetSizes(buildContext)
+	if minfo.name == "describe" {
+		conf.ParserMode = parser.ParseComments // for deprecation notices
+	}
 
 	// Determine initial packages.
 	args, err := conf.FromArgs(args, true)
@@ -256,6 +302,8 @@ func Query(args []string, mode, pos string, ptalog io.Writer, buildContext *buil
 	if err != nil {
 		return nil, err
 	}
+	o.build = buildContext
+	o.typeSizes = conf.TypeChecker.Sizes
 
 	qpos, err := ParseQueryPos(iprog, pos, minfo.needs&needExactPos != 0)
 	if err != nil && minfo.needs&(needPos|needExactPos) != 0 {
@@ -266,7 +314,7 @@ func Query(args []string, mode, pos string, ptalog io.Writer, buildContext *buil
 	// Release the other ASTs and type info to the GC.
 	iprog = nil
 
-	return o.query(minfo, qpos)
+	return o.query(minfo, arg, qpos)
 }
 
 // reduceScope is called for one-shot queries that need only a single
@@ -399,17 +447,22 @@ func newOracle(iprog *loader.Program, ptalog io.Writer, needs int, reflection bo
 // "what" query, which needs to access the go/build.Context.
 //
 func (o *Oracle) Query(mode string, qpos *QueryPos) (*Result, error) {
+	mode, arg := splitMode(mode)
 	minfo := findMode(mode)
 	if minfo == nil {
 		return nil, fmt.Errorf("invalid mode type: %q", mode)
 	}
-	return o.query(minfo, qpos)
+	if err := checkModeArg(minfo, arg); err != nil {
+		return nil, err
+	}
+	return o.query(minfo, arg, qpos)
 }
 
-func (o *Oracle) query(minfo *modeInfo, qpos *QueryPos) (*Result, error) {
+func (o *Oracle) query(minfo *modeInfo, arg string, qpos *QueryPos) (*Result, error) {
 	// Clear out residue of previous query (for long-running clients).
 	o.ptaConfig.Queries = nil
 	o.ptaConfig.IndirectQueries = nil
+	o.arg = arg
 
 	res := &Result{
 		mode: minfo.name,
@@ -465,6 +518,65 @@ func (res *Result) WriteTo(out io.Writer) {
 
 // ---------- Utilities ----------
 
+// platform returns the GOOS/GOARCH of the analysis target,
+// e.g. "linux/amd64".
+func (o *Oracle) platform() string {
+	ctxt := o.build
+	if ctxt == nil {
+		ctxt = &build.Default
+	}
+	return ctxt.GOOS + "/" + ctxt.GOARCH
+}
+
+// sizes returns the sizing functions with which the program was
+// type-checked.
+func (o *Oracle) sizes() types.Sizes {
+	if o.typeSizes != nil {
+		return o.typeSizes
+	}
+	return &types.StdSizes{WordSize: 8, MaxAlign: 8} // go/types' default
+}
+
+// targetSizes returns the sizing functions for the GOARCH of ctxt.
+func targetSizes(ctxt *build.Context) types.Sizes {
+	if ctxt == nil {
+		ctxt = &build.Default
+	}
+	var wordSize int64 = 8
+	switch ctxt.GOARCH {
+	case "386", "arm", "amd64p32":
+		wordSize = 4
+	}
+	return &types.StdSizes{WordSize: wordSize, MaxAlign: wordSize}
+}
+
+// constString returns the string form of the constant value v, or ""
+// if v is nil.  Unlike v.String(), it formats non-integer numbers in
+// decimal floating-point notation, e.g. 0.5, not 1/2, and complex
+// numbers without parentheses, e.g. 1 + 2i.
+func constString(v exact.Value) string {
+	if v == nil {
+		return ""
+	}
+	switch v.Kind() {
+	case exact.Float:
+		f, _ := exact.Float64Val(v)
+		return strconv.FormatFloat(f, 'g', -1, 64)
+	case exact.Complex:
+		re, im := exact.Real(v), exact.Imag(v)
+		if exact.Sign(re) == 0 {
+			return constString(im) + "i"
+		}
+		op := " + "
+		if exact.Sign(im) < 0 {
+			op = " - "
+			im = exact.UnaryOp(token.SUB, im, 0)
+		}
+		return constString(re) + op + constString(im) + "i"
+	}
+	return v.String()
+}
+
 // buildSSA constructs the SSA representation of Go-source function bodies.
 // Not needed in simpler modes, e.g. freevars.
 //
@@ -481,6 +593,18 @@ func ptrAnalysis(o *Oracle) *pointer.Result {
 	return result
 }
 
+// callGraph runs the pointer analysis and returns the resulting call
+// graph.  It does not alter the Oracle's pointer analysis configuration.
+func callGraph(o *Oracle) (*callgraph.Graph, error) {
+	conf := o.ptaConfig
+	conf.BuildCallGraph = true
+	result, err := pointer.Analyze(&conf)
+	if err != nil {
+		return nil, err
+	}
+	return result.CallGraph, nil
+}
+
 // unparen returns e with any enclosing parentheses stripped.
 func unparen(e ast.Expr) ast.Expr {
 	for {
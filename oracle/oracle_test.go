@@ -14,6 +14,7 @@ package oracle_test
 // where verb is the query mode (e.g. "callers"), id is a unique name
 // for this query, and "select" is a regular expression matching the
 // substring of the current line that is the query's input selection.
+// Modes that take an argument are written verb=arg, e.g. "convert=int".
 //
 // The expected output for each query is provided in the accompanying
 // .golden file.
@@ -86,7 +87,7 @@ func parseQueries(t *testing.T, filename string) []*query {
 	queriesById := make(map[string]*query)
 
 	// Find all annotations of these forms:
-	expectRe := regexp.MustCompile(`@([a-z]+)\s+(\S+)\s+(\".*)$`) // @verb id "regexp"
+	expectRe := regexp.MustCompile(`@([a-z]+(?:=\S+)?)\s+(\S+)\s+(\".*)$`) // @verb[=arg] id "regexp"
 	for _, c := range f.Comments {
 		text := strings.TrimSpace(c.Text())
 		if text == "" || text[0] != '@' {
@@ -170,6 +171,8 @@ func doQuery(out io.Writer, q *query, useJson bool) {
 
 	var buildContext = build.Default
 	buildContext.GOPATH = "testdata"
+	buildContext.GOOS = "linux" // make target-dependent output deterministic
+	buildContext.GOARCH = "amd64"
 	res, err := oracle.Query([]string{q.filename},
 		q.verb,
 		q.queryPos,
@@ -202,16 +205,28 @@ func TestOracle(t *testing.T) {
 	}
 
 	for _, filename := range []string{
+		"testdata/src/main/blank.go",
 		"testdata/src/main/calls.go",
+		"testdata/src/main/capture.go",
+		"testdata/src/main/complexity.go",
+		"testdata/src/main/context.go",
+		"testdata/src/main/convert.go",
 		"testdata/src/main/callgraph.go",
 		"testdata/src/main/callgraph2.go",
+		"testdata/src/main/depth.go",
 		"testdata/src/main/describe.go",
+		"testdata/src/main/dom.go",
+		"testdata/src/main/dynamic.go",
 		"testdata/src/main/freevars.go",
 		"testdata/src/main/implements.go",
+		"testdata/src/main/initorder.go",
+		"testdata/src/main/mapkey.go",
 		"testdata/src/main/imports.go",
 		"testdata/src/main/peers.go",
 		"testdata/src/main/pointsto.go",
+		"testdata/src/main/recursion.go",
 		"testdata/src/main/reflection.go",
+		"testdata/src/main/spawn.go",
 		"testdata/src/main/what.go",
 		// JSON:
 		// TODO(adonovan): most of these are very similar; combine them.
@@ -219,6 +234,7 @@ func TestOracle(t *testing.T) {
 		"testdata/src/main/calls-json.go",
 		"testdata/src/main/peers-json.go",
 		"testdata/src/main/describe-json.go",
+		"testdata/src/main/dynamic-json.go",
 		"testdata/src/main/implements-json.go",
 		"testdata/src/main/pointsto-json.go",
 		"testdata/src/main/referrers-json.go",
@@ -317,3 +333,99 @@ var x int
 		t.Errorf("Query output differs; want <<%s>>, got <<%s>>\n", want, got)
 	}
 }
+
+func TestDescribePlatform(t *testing.T) {
+	filename := "testdata/src/main/platform.go"
+	data, err := ioutil.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Select the type T in "type T struct{...}".
+	start := bytes.Index(data, []byte("type T")) + len("type ")
+	pos := fmt.Sprintf("%s:#%d,#%d", filename, start, start+1)
+	// Select the type U in "type U struct{...}".
+	start = bytes.Index(data, []byte("type U")) + len("type ")
+	uPos := fmt.Sprintf("%s:#%d,#%d", filename, start, start+1)
+	// Select the reference to big in "var _ int64 = big".
+	start = bytes.Index(data, []byte("= big")) + len("= ")
+	bigPos := fmt.Sprintf("%s:#%d,#%d", filename, start, start+1)
+
+	for _, test := range []struct {
+		goarch, platform, desc, uDesc string
+		arrayLen                      bool // big is a valid array length
+	}{
+		{"amd64", "linux/amd64", "definition of type T (size 16, align 8)", "definition of type U (size 16, align 8)", true},
+		{"386", "linux/386", "definition of type T (size 8, align 4)", "definition of type U (size 12, align 4)", false},
+	} {
+		var buildContext = build.Default
+		buildContext.GOPATH = "testdata"
+		buildContext.GOOS = "linux"
+		buildContext.GOARCH = test.goarch
+		res, err := oracle.Query([]string{filename}, "describe", pos, nil, &buildContext, false)
+		if err != nil {
+			t.Errorf("oracle.Query(%q) for %s failed: %s", pos, test.goarch, err)
+			continue
+		}
+		d := res.Serial().Describe
+		if d.Type == nil {
+			t.Errorf("%s: got %s, want type description", test.goarch, d.Desc)
+			continue
+		}
+		if d.Type.Platform != test.platform {
+			t.Errorf("%s: got platform %q, want %q", test.goarch, d.Type.Platform, test.platform)
+		}
+		if d.Desc != test.desc {
+			t.Errorf("%s: got description %q, want %q", test.goarch, d.Desc, test.desc)
+		}
+
+		res, err = oracle.Query([]string{filename}, "describe", uPos, nil, &buildContext, false)
+		if err != nil {
+			t.Errorf("oracle.Query(%q) for %s failed: %s", uPos, test.goarch, err)
+			continue
+		}
+		if d := res.Serial().Describe; d.Desc != test.uDesc {
+			t.Errorf("%s: got description %q, want %q", test.goarch, d.Desc, test.uDesc)
+		}
+
+		res, err = oracle.Query([]string{filename}, "describe", bigPos, nil, &buildContext, false)
+		if err != nil {
+			t.Errorf("oracle.Query(%q) for %s failed: %s", bigPos, test.goarch, err)
+			continue
+		}
+		if v := res.Serial().Describe.Value; v == nil || v.ArrayLen != test.arrayLen {
+			t.Errorf("%s: got %+v, want ArrayLen=%t", test.goarch, v, test.arrayLen)
+		}
+	}
+}
+
+func TestDescribeTestFiles(t *testing.T) {
+	for _, test := range []struct {
+		filename    string
+		test, xtest bool
+	}{
+		{"testdata/src/main/platform.go", false, false},
+		{"testdata/src/main/testfile_test.go", true, false},
+		{"testdata/src/main/xtestfile_test.go", true, true},
+	} {
+		data, err := ioutil.ReadFile(test.filename)
+		if err != nil {
+			t.Fatal(err)
+		}
+		// Select the name T in "type T" or "var T".
+		start := bytes.Index(data, []byte(" T ")) + 1
+		pos := fmt.Sprintf("%s:#%d,#%d", test.filename, start, start+1)
+
+		var buildContext = build.Default
+		buildContext.GOPATH = "testdata"
+		res, err := oracle.Query([]string{test.filename}, "describe", pos, nil, &buildContext, false)
+		if err != nil {
+			t.Errorf("oracle.Query(%q) failed: %s", pos, err)
+			continue
+		}
+		d := res.Serial().Describe
+		if d.Test != test.test || d.XTest != test.xtest {
+			t.Errorf("%s: got test=%t xtest=%t, want test=%t xtest=%t",
+				test.filename, d.Test, d.XTest, test.test, test.xtest)
+		}
+	}
+}
@@ -197,6 +197,7 @@ func (r *pointstoResult) display(printf printfFunc) {
 				if len(ptr.labels) > 0 {
 					printf(obj, "\t%s, may point to:", r.qpos.TypeString(ptr.typ))
 					printLabels(printf, ptr.labels, "\t\t")
+					printContexts(printf, r.qpos, ptr.labels, "\t\t")
 				} else {
 					printf(obj, "\t%s", r.qpos.TypeString(ptr.typ))
 				}
@@ -210,6 +211,7 @@ func (r *pointstoResult) display(printf printfFunc) {
 			printf(r.qpos, "this %s may point to these objects:",
 				r.qpos.TypeString(r.typ))
 			printLabels(printf, ptr.labels, "\t")
+			printContexts(printf, r.qpos, ptr.labels, "\t")
 		} else {
 			printf(r.qpos, "this %s may not point to anything.",
 				r.qpos.TypeString(r.typ))
@@ -218,6 +220,12 @@ func (r *pointstoResult) display(printf printfFunc) {
 }
 
 func (r *pointstoResult) toSerial(res *serial.Result, fset *token.FileSet) {
+	// For an interface, each element records the number of
+	// distinct concrete types in the combined points-to set.
+	var dynTypes int
+	if isInterface(r.typ) {
+		dynTypes = len(r.ptrs)
+	}
 	var pts []serial.PointsTo
 	for _, ptr := range r.ptrs {
 		var namePos string
@@ -229,12 +237,19 @@ func (r *pointstoResult) toSerial(res *serial.Result, fset *token.FileSet) {
 			labels = append(labels, serial.PointsToLabel{
 				Pos:  fset.Position(l.Pos()).String(),
 				Desc: l.String(),
+				Kind: labelKind(l),
 			})
 		}
+		var contexts int
+		if n := labelContexts(ptr.labels); n > 1 {
+			contexts = n
+		}
 		pts = append(pts, serial.PointsTo{
-			Type:    r.qpos.TypeString(ptr.typ),
-			NamePos: namePos,
-			Labels:  labels,
+			Type:     r.qpos.TypeString(ptr.typ),
+			NamePos:  namePos,
+			Labels:   labels,
+			Contexts: contexts,
+			DynTypes: dynTypes,
 		})
 	}
 	res.PointsTo = pts
@@ -255,6 +270,27 @@ func (a byPosAndString) Less(i, j int) bool {
 }
 func (a byPosAndString) Swap(i, j int) { a[i], a[j] = a[j], a[i] }
 
+// labelKind returns the allocation kind of the object denoted by
+// label l: "global", "stack", "heap" or "func", or "" if unknown.
+//
+func labelKind(l *pointer.Label) string {
+	switch v := l.Value().(type) {
+	case *ssa.Global:
+		return "global"
+	case *ssa.Function:
+		return "func"
+	case *ssa.Alloc:
+		if v.Heap {
+			return "heap"
+		}
+		return "stack"
+	case *ssa.Call, *ssa.MakeMap, *ssa.MakeChan, *ssa.MakeSlice,
+		*ssa.Convert, *ssa.MakeInterface:
+		return "heap"
+	}
+	return ""
+}
+
 func printLabels(printf printfFunc, labels []*pointer.Label, prefix string) {
 	// TODO(adonovan): due to context-sensitivity, many of these
 	// labels may differ only by context, which isn't apparent.
@@ -262,3 +298,41 @@ func printLabels(printf printfFunc, labels []*pointer.Label, prefix string) {
 		printf(label, "%s%s", prefix, label)
 	}
 }
+
+// printContexts prints a note if some of the labels differ only by
+// the context in which their allocation site was analyzed, since
+// such labels are indistinguishable when printed.
+//
+func printContexts(printf printfFunc, pos interface{}, labels []*pointer.Label, prefix string) {
+	if n := labelContexts(labels); n > 1 {
+		printf(pos, "%s(up to %d of these objects differ only by analysis context)", prefix, n)
+	}
+}
+
+// labelContexts returns the greatest number of labels in labels that
+// share an allocation site.  The pointer analysis names heap objects
+// by allocation site and context, so such labels arise from analyzing
+// the site anew in distinct calling contexts.
+//
+func labelContexts(labels []*pointer.Label) int {
+	type site struct {
+		key  interface{} // ssa.Value, types.Type or string
+		path string
+	}
+	counts := make(map[site]int)
+	max := 0
+	for _, l := range labels {
+		var key interface{} = l.String()
+		if v := l.Value(); v != nil {
+			key = v
+		} else if t := l.ReflectType(); t != nil {
+			key = t.String()
+		}
+		s := site{key, l.Path()}
+		counts[s]++
+		if n := counts[s]; n > max {
+			max = n
+		}
+	}
+	return max
+}
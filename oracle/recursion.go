@@ -0,0 +1,134 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package oracle
+
+import (
+	"fmt"
+	"go/token"
+	"sort"
+
+	"code.google.com/p/go.tools/go/callgraph"
+	"code.google.com/p/go.tools/go/ssa"
+	"code.google.com/p/go.tools/oracle/serial"
+)
+
+// Recursion reports whether the function immediately enclosing the
+// specified source location may call itself, according to the call
+// graph.  A function is mutually recursive with the other members of
+// its strongly connected component, i.e. the functions that it both
+// reaches and is reached by.
+//
+func recursion(o *Oracle, qpos *QueryPos) (queryResult, error) {
+	pkg := o.prog.Package(qpos.info.Pkg)
+	if pkg == nil {
+		return nil, fmt.Errorf("no SSA package")
+	}
+	if !ssa.HasEnclosingFunction(pkg, qpos.path) {
+		return nil, fmt.Errorf("this position is not inside a function")
+	}
+
+	buildSSA(o)
+
+	target := ssa.EnclosingFunction(pkg, qpos.path)
+	if target == nil {
+		return nil, fmt.Errorf("no SSA function built for this location (dead code?)")
+	}
+
+	cg, err := callGraph(o)
+	if err != nil {
+		return nil, err
+	}
+
+	r := &recursionResult{target: target}
+	n := cg.Nodes[target]
+	if n == nil {
+		return r, nil // unreachable
+	}
+	for _, e := range n.Out {
+		if e.Callee == n {
+			r.direct = true
+		}
+	}
+
+	// reachable returns the set of nodes reachable from n
+	// by following edges in the specified direction.
+	reachable := func(forward bool) map[*callgraph.Node]bool {
+		seen := map[*callgraph.Node]bool{n: true}
+		for queue := []*callgraph.Node{n}; len(queue) > 0; queue = queue[1:] {
+			edges := queue[0].In
+			if forward {
+				edges = queue[0].Out
+			}
+			for _, e := range edges {
+				next := e.Caller
+				if forward {
+					next = e.Callee
+				}
+				if !seen[next] {
+					seen[next] = true
+					queue = append(queue, next)
+				}
+			}
+		}
+		return seen
+	}
+	callers := reachable(false)
+	for m := range reachable(true) {
+		if m != n && callers[m] {
+			r.cycle = append(r.cycle, m.Func)
+		}
+	}
+	sort.Sort(byFuncString(r.cycle))
+	return r, nil
+}
+
+type byFuncString []*ssa.Function
+
+func (s byFuncString) Len() int           { return len(s) }
+func (s byFuncString) Less(i, j int) bool { return s[i].String() < s[j].String() }
+func (s byFuncString) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+type recursionResult struct {
+	target *ssa.Function
+	direct bool            // target calls itself
+	cycle  []*ssa.Function // other functions in its strongly connected component
+}
+
+func (r *recursionResult) kind() string {
+	switch {
+	case r.cycle != nil:
+		return "mutual"
+	case r.direct:
+		return "direct"
+	}
+	return "none"
+}
+
+func (r *recursionResult) display(printf printfFunc) {
+	switch r.kind() {
+	case "mutual":
+		printf(r.target, "%s is mutually recursive with these %d functions:", r.target, len(r.cycle))
+		for _, fn := range r.cycle {
+			printf(fn, "\t%s", fn)
+		}
+	case "direct":
+		printf(r.target, "%s is directly recursive", r.target)
+	default:
+		printf(r.target, "%s is not recursive", r.target)
+	}
+}
+
+func (r *recursionResult) toSerial(res *serial.Result, fset *token.FileSet) {
+	var cycle []string
+	for _, fn := range r.cycle {
+		cycle = append(cycle, fn.String())
+	}
+	res.Recursion = &serial.Recursion{
+		Pos:    fset.Position(r.target.Pos()).String(),
+		Target: r.target.String(),
+		Kind:   r.kind(),
+		Cycle:  cycle,
+	}
+}
@@ -24,6 +24,18 @@ type Peers struct {
 	Receives []string `json:"receives,omitempty"` // locations of aliased <-ch ops
 }
 
+// A Convert is the result of a 'convert' query.
+// It describes whether the selected expression is assignable to the
+// target type and, if not, a conversion that would make it so.
+type Convert struct {
+	Pos        string `json:"pos"`                  // location of the selected expression
+	Type       string `json:"type"`                 // type of the selected expression
+	Value      string `json:"value,omitempty"`      // value of the selected expression, if constant
+	Target     string `json:"target"`               // the target type
+	Assignable bool   `json:"assignable,omitempty"` // expression is assignable to target
+	Conversion string `json:"conversion,omitempty"` // explicit conversion, if needed and possible
+}
+
 // A Referrers is the result of a 'referrers' query.
 type Referrers struct {
 	Pos    string   `json:"pos"`              // location of the query reference
@@ -78,7 +90,7 @@ type CallGraph struct {
 }
 
 // A CallStack is the result of a 'callstack' query.
-// It indicates an arbitrary path from the root of the callgraph to
+// It indicates a shortest path from the root of the callgraph to
 // the query function.
 //
 // If the Callers slice is empty, the function was unreachable in this
@@ -87,6 +99,58 @@ type CallStack struct {
 	Pos     string   `json:"pos"`     // location of the selected function
 	Target  string   `json:"target"`  // the selected function
 	Callers []Caller `json:"callers"` // enclosing calls, innermost first.
+	Depth   int      `json:"depth"`   // length of the path from an entry point, or -1 if unreachable
+}
+
+// A Goroutines is the result of a 'goroutines' query.
+// It lists the go statements that may be executed, directly or
+// transitively, by a call to the selected function.
+type Goroutines struct {
+	Pos    string   `json:"pos"`             // location of the selected function
+	Target string   `json:"target"`          // the selected function
+	Sites  []string `json:"sites,omitempty"` // locations of go statements
+}
+
+// A MapKey is the result of a 'mapkey' query.
+// It lists the map types in the program whose key type is the
+// selected type.
+type MapKey struct {
+	Type string   `json:"type"`           // the selected type
+	Maps []string `json:"maps,omitempty"` // locations of map types keyed by it
+}
+
+// A Recursion is the result of a 'recursion' query.
+// It describes whether the selected function may call itself,
+// according to the call graph.
+type Recursion struct {
+	Pos    string   `json:"pos"`             // location of the selected function
+	Target string   `json:"target"`          // the selected function
+	Kind   string   `json:"kind"`            // "direct", "mutual" or "none"
+	Cycle  []string `json:"cycle,omitempty"` // other functions mutually recursive with it
+}
+
+// An SSA is the result of an 'ssa' query.
+// For an expression, it describes the SSA basic block containing the
+// instruction that computes it, if any, the closures capturing the
+// local variable it denotes, and the cyclomatic complexity of the
+// function it denotes; for a blank import, it lists the init functions
+// of the imported package.
+type SSA struct {
+	Desc       string    `json:"desc"`                 // description of the selection
+	Pos        string    `json:"pos"`                  // location of the selection
+	Block      *SSABlock `json:"block,omitempty"`      // nil => not computed by an instruction
+	Inits      []string  `json:"inits,omitempty"`      // locations of the imported package's init functions
+	Captors    []string  `json:"captors,omitempty"`    // locations of closures capturing a local variable
+	Complexity *int      `json:"complexity,omitempty"` // cyclomatic complexity of a function with a body
+}
+
+// An SSABlock describes an SSA basic block and its position in the
+// dominator tree of its function.
+type SSABlock struct {
+	Func  string `json:"func"`  // enclosing function
+	Index int    `json:"index"` // block index within function
+	Idom  int    `json:"idom"`  // index of immediate dominator, or -1 for a root
+	Depth int    `json:"depth"` // depth in dominator tree
 }
 
 // A FreeVar is one element of the slice returned by a 'freevars'
@@ -150,8 +214,9 @@ type What struct {
 //    - and their subelements, e.g. "alloc.y[*].z"
 //
 type PointsToLabel struct {
-	Pos  string `json:"pos"`  // location of syntax that allocated the object
-	Desc string `json:"desc"` // description of the label
+	Pos  string `json:"pos"`            // location of syntax that allocated the object
+	Desc string `json:"desc"`           // description of the label
+	Kind string `json:"kind,omitempty"` // one of {global,stack,heap,func}, if known
 }
 
 // A PointsTo is one element of the result of a 'pointsto' query on an
@@ -165,17 +230,61 @@ type PointsToLabel struct {
 // dynamic types needn't be concrete.
 //
 type PointsTo struct {
-	Type    string          `json:"type"`              // (concrete) type of the pointer
-	NamePos string          `json:"namepos,omitempty"` // location of type defn, if Named
-	Labels  []PointsToLabel `json:"labels,omitempty"`  // pointed-to objects
+	Type     string          `json:"type"`               // (concrete) type of the pointer
+	NamePos  string          `json:"namepos,omitempty"`  // location of type defn, if Named
+	Labels   []PointsToLabel `json:"labels,omitempty"`   // pointed-to objects
+	Contexts int             `json:"contexts,omitempty"` // greatest number of labels for one allocation site, if > 1
+	DynTypes int             `json:"dyntypes,omitempty"` // number of distinct concrete types an interface may hold
 }
 
 // A DescribeValue is the additional result of a 'describe' query
 // if the selection indicates a value or expression.
 type DescribeValue struct {
-	Type   string `json:"type"`             // type of the expression
-	Value  string `json:"value,omitempty"`  // value of the expression, if constant
-	ObjPos string `json:"objpos,omitempty"` // location of the definition, if an Ident
+	Type       string              `json:"type"`                 // type of the expression
+	Kind       string              `json:"kind,omitempty"`       // "function", "method" or "interface method", if a func
+	Receiver   string              `json:"receiver,omitempty"`   // "value" or "pointer" receiver of a concrete method call
+	Stmt       string              `json:"stmt,omitempty"`       // "defer" or "go", if the call of such a statement
+	ReturnsErr bool                `json:"returnserr,omitempty"` // the function's last result is of type error
+	Reader     bool                `json:"reader,omitempty"`     // type of the expression implements io.Reader
+	Writer     bool                `json:"writer,omitempty"`     // type of the expression implements io.Writer
+	Pure       bool                `json:"pure,omitempty"`       // expression is free of side effects (no calls or channel receives)
+	Value      string              `json:"value,omitempty"`      // value of the expression, if constant
+	Folds      []DescribeFold      `json:"folds,omitempty"`      // constant folding steps, in order, if a constant non-identifier expression
+	Deprecated string              `json:"deprecated,omitempty"` // deprecation notice in the doc comment of the referenced object, if any
+	ObjPos     string              `json:"objpos,omitempty"`     // location of the definition, if an Ident
+	ArrayLen   bool                `json:"arraylen,omitempty"`   // value is a constant usable as an array length
+	FitsIn     []string            `json:"fitsin,omitempty"`     // smallest signed and unsigned sized integer types able to represent a constant integer value
+	Impls      []DescribeMethod    `json:"impls,omitempty"`      // concrete methods implementing an interface method
+	Interfaces []DescribeInterface `json:"interfaces,omitempty"` // named interfaces having a method like a concrete method
+	StaticSize *int64              `json:"staticsize,omitempty"` // size in bytes of a package-level variable, for the target platform
+	Init       *DescribeInit       `json:"init,omitempty"`       // initialization of a package-level variable
+}
+
+// A DescribeInterface describes a named interface type.
+type DescribeInterface struct {
+	Name string `json:"name"` // name of the interface type
+	Pos  string `json:"pos"`  // location of its declaration
+}
+
+// A DescribeFold describes one step in the folding of a constant
+// expression: an operation, with the values of its operands
+// substituted, and its value; e.g. {"2 * 3", "6"}.
+type DescribeFold struct {
+	Expr  string `json:"expr"`  // the operation
+	Value string `json:"value"` // its constant value
+}
+
+// A DescribeInit describes the initialization of a package-level
+// variable that has an initializer.
+type DescribeInit struct {
+	Step int               `json:"step"`           // 1-based position of the initializer in package initialization order
+	Deps []DescribeInitDep `json:"deps,omitempty"` // package-level variables on which the initializer depends
+}
+
+// A DescribeInitDep is an initialization dependency of a package-level variable.
+type DescribeInitDep struct {
+	Name string `json:"name"` // name of the variable
+	Pos  string `json:"pos"`  // location of the variable's definition
 }
 
 type DescribeMethod struct {
@@ -183,13 +292,35 @@ type DescribeMethod struct {
 	Pos  string `json:"pos"`  // location of the method's definition
 }
 
+// A DescribeField describes a field of a struct type.
+type DescribeField struct {
+	Name      string `json:"name"`                // field name
+	Type      string `json:"type"`                // field type
+	Tag       string `json:"tag,omitempty"`       // field tag, if any
+	Embedded  bool   `json:"embedded,omitempty"`  // field is an anonymous (embedded) field
+	Offset    int64  `json:"offset"`              // byte offset of the field within the struct
+	Size      int64  `json:"size"`                // size of the field in bytes
+	Straddles bool   `json:"straddles,omitempty"` // field spans a 64-byte cache line boundary
+	Pos       string `json:"pos"`                 // location of the field's declaration
+}
+
 // A DescribeType is the additional result of a 'describe' query
 // if the selection indicates a type.
 type DescribeType struct {
-	Type    string           `json:"type"`              // the string form of the type
-	NamePos string           `json:"namepos,omitempty"` // location of definition of type, if named
-	NameDef string           `json:"namedef,omitempty"` // underlying definition of type, if named
-	Methods []DescribeMethod `json:"methods,omitempty"` // methods of the type
+	Type       string           `json:"type"`                 // the string form of the type
+	NamePos    string           `json:"namepos,omitempty"`    // location of definition of type, if named
+	NameDef    string           `json:"namedef,omitempty"`    // underlying definition of type, if named
+	Methods    []DescribeMethod `json:"methods,omitempty"`    // methods of the type
+	Fields     []DescribeField  `json:"fields,omitempty"`     // fields of the type, if a struct, in declaration order
+	ConvTo     []string         `json:"convto,omitempty"`     // predeclared types (and []byte, []rune) to which the type is convertible
+	ConvFrom   []string         `json:"convfrom,omitempty"`   // predeclared types (and []byte, []rune) convertible to the type
+	Ambiguous  []string         `json:"ambiguous,omitempty"`  // names of embedded methods not promoted due to ambiguity
+	IsError    bool             `json:"iserror,omitempty"`    // type implements the error interface
+	ZeroSize   bool             `json:"zerosize,omitempty"`   // values of the type occupy no memory, e.g. struct{} or [0]int
+	API        []DescribeMethod `json:"api,omitempty"`        // exported functions and methods of the package whose signatures mention the type
+	Deprecated string           `json:"deprecated,omitempty"` // deprecation notice in the doc comment of a named type, if any
+	Qualified  string           `json:"qualified,omitempty"`  // fully qualified name of a function-local type, e.g. "pkg.f.T"
+	Platform   string           `json:"platform,omitempty"`   // GOOS/GOARCH assumed for sizes, e.g. "linux/amd64"
 }
 
 type DescribeMember struct {
@@ -206,6 +337,7 @@ type DescribeMember struct {
 type DescribePackage struct {
 	Path    string            `json:"path"`              // import path of the package
 	Members []*DescribeMember `json:"members,omitempty"` // accessible members of the package
+	Blank   bool              `json:"blank,omitempty"`   // package is imported for its side effects only
 }
 
 // A Describe is the result of a 'describe' query.
@@ -215,6 +347,12 @@ type Describe struct {
 	Desc   string `json:"desc"`             // description of the selected syntax node
 	Pos    string `json:"pos"`              // location of the selected syntax node
 	Detail string `json:"detail,omitempty"` // one of {package, type, value}, or "".
+	Test   bool   `json:"test,omitempty"`   // node is in a _test.go file
+	XTest  bool   `json:"xtest,omitempty"`  // node is in an external test package
+
+	// Enclosing is populated for statements that lie within a
+	// loop, switch or select statement.
+	Enclosing *DescribeEnclosing `json:"enclosing,omitempty"`
 
 	// At most one of the following fields is populated:
 	// the one specified by 'detail'.
@@ -223,6 +361,15 @@ type Describe struct {
 	Value   *DescribeValue   `json:"value,omitempty"`
 }
 
+// A DescribeEnclosing describes the innermost loop, switch or select
+// statement enclosing a selected statement; it is the default target
+// of an unlabelled break, and of continue if it is a loop.
+type DescribeEnclosing struct {
+	Desc string `json:"desc"`           // description of the enclosing statement
+	Pos  string `json:"pos"`            // location of the enclosing statement
+	Loop bool   `json:"loop,omitempty"` // enclosing statement is a for or range loop
+}
+
 type PTAWarning struct {
 	Pos     string `json:"pos"`     // location associated with warning
 	Message string `json:"message"` // warning message
@@ -242,13 +389,18 @@ type Result struct {
 	Callers    []Caller    `json:"callers,omitempty"`
 	Callgraph  []CallGraph `json:"callgraph,omitempty"`
 	Callstack  *CallStack  `json:"callstack,omitempty"`
+	Convert    *Convert    `json:"convert,omitempty"`
 	Definition *Definition `json:"definition,omitempty"`
 	Describe   *Describe   `json:"describe,omitempty"`
 	Freevars   []*FreeVar  `json:"freevars,omitempty"`
+	Goroutines *Goroutines `json:"goroutines,omitempty"`
 	Implements *Implements `json:"implements,omitempty"`
+	MapKey     *MapKey     `json:"mapkey,omitempty"`
 	Peers      *Peers      `json:"peers,omitempty"`
 	PointsTo   []PointsTo  `json:"pointsto,omitempty"`
+	Recursion  *Recursion  `json:"recursion,omitempty"`
 	Referrers  *Referrers  `json:"referrers,omitempty"`
+	SSA        *SSA        `json:"ssa,omitempty"`
 	What       *What       `json:"what,omitempty"`
 
 	Warnings []PTAWarning `json:"warnings,omitempty"` // warnings from pointer analysis
@@ -0,0 +1,318 @@
+// Copyright 2014 The Go Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package oracle
+
+import (
+	"fmt"
+	"go/ast"
+	"go/token"
+	"strings"
+
+	"code.google.com/p/go.tools/astutil"
+	"code.google.com/p/go.tools/go/ssa"
+	"code.google.com/p/go.tools/go/types"
+	"code.google.com/p/go.tools/oracle/serial"
+)
+
+// ssaQuery reports facts about the SSA form of the selection.
+//
+// For an expression, it reports the basic block of the instruction
+// that computes its value, and that block's position in the dominator
+// tree of its function.  For a local variable, it also lists the
+// closures that capture it; for a function, it reports its cyclomatic
+// complexity.
+//
+// For a blank import, it lists the init functions of the imported
+// package, in the order in which they are called.
+//
+func ssaQuery(o *Oracle, qpos *QueryPos) (queryResult, error) {
+	path, action := findInterestingNode(qpos.info, qpos.path)
+	switch action {
+	case actionExpr:
+		// ok
+	case actionPackage:
+		return ssaBlankImport(o, qpos, path)
+	default:
+		return nil, fmt.Errorf("ssa wants an expression or blank import; got %s",
+			astutil.NodeDescription(qpos.path[0]))
+	}
+	expr, ok := path[0].(ast.Expr)
+	if !ok {
+		return nil, fmt.Errorf("ssa wants an expression; got %s",
+			astutil.NodeDescription(path[0]))
+	}
+	var obj types.Object
+	if id, ok := expr.(*ast.Ident); ok {
+		obj = qpos.info.ObjectOf(id)
+	}
+
+	var local *types.Var
+	var captors []*ssa.Function
+	if v, ok := obj.(*types.Var); ok && isLocal(v) {
+		local = v
+		captors = capturingClosures(o, qpos, v)
+	}
+
+	var fn *types.Func
+	complexity := -1
+	if f, ok := obj.(*types.Func); ok {
+		fn = f
+		complexity = cyclomaticComplexity(o, f)
+	}
+
+	return &ssaResult{
+		expr:       expr,
+		block:      ssaBlockForExpr(o, qpos, obj, path),
+		local:      local,
+		captors:    captors,
+		fn:         fn,
+		complexity: complexity,
+	}, nil
+}
+
+// ssaBlockForExpr returns the basic block containing the SSA
+// instruction that computes the value of the selected expression, or
+// nil if there is none, e.g. for constants, parameters and
+// package-level objects.
+//
+func ssaBlockForExpr(o *Oracle, qpos *QueryPos, obj types.Object, path []ast.Node) *ssa.BasicBlock {
+	var v ssa.Value
+	switch obj.(type) {
+	case nil:
+		v, _, _ = ssaValueForExpr(o.prog, qpos.info, path)
+	case *types.Var:
+		v, _, _ = ssaValueForIdent(o.prog, qpos.info, obj, path)
+	}
+	if instr, ok := v.(ssa.Instruction); ok {
+		return instr.Block()
+	}
+	return nil
+}
+
+// isLocal reports whether v is a local variable or parameter, as
+// opposed to a package-level variable or a struct field.
+func isLocal(v *types.Var) bool {
+	return !v.IsField() && v.Pkg() != nil && v.Parent() != v.Pkg().Scope()
+}
+
+// capturingClosures returns the anonymous functions, at any depth
+// within the function declaring the local variable v, that capture v
+// as a free variable.
+func capturingClosures(o *Oracle, qpos *QueryPos, v *types.Var) []*ssa.Function {
+	pkg := o.prog.Package(qpos.info.Pkg)
+	pkg.Build()
+
+	var path []ast.Node
+	for _, f := range qpos.info.Files {
+		if f.Pos() <= v.Pos() && v.Pos() < f.End() {
+			path, _ = astutil.PathEnclosingInterval(f, v.Pos(), v.Pos())
+			break
+		}
+	}
+	fn := ssa.EnclosingFunction(pkg, path)
+	if fn == nil {
+		return nil // e.g. dead code
+	}
+
+	// A FreeVar's position is that of the captured variable.
+	var captors []*ssa.Function
+	var visit func(fn *ssa.Function)
+	visit = func(fn *ssa.Function) {
+		for _, anon := range fn.AnonFuncs {
+			for _, fv := range anon.FreeVars {
+				if fv.Pos() == v.Pos() && fv.Name() == v.Name() {
+					captors = append(captors, anon)
+					break
+				}
+			}
+			visit(anon)
+		}
+	}
+	visit(fn)
+	return captors
+}
+
+// cyclomaticComplexity returns the cyclomatic complexity of the
+// function obj, computed from the control-flow graph of its SSA form
+// as edges - nodes + 2, or -1 if obj is abstract or has no body.
+// Blocks that return (or panic) are treated as having an edge to a
+// single synthetic exit node.  The recover block, which is entered
+// only by a panic, is ignored.
+//
+func cyclomaticComplexity(o *Oracle, obj *types.Func) int {
+	buildSSA(o)
+	fn := o.prog.FuncValue(obj)
+	if fn == nil || fn.Blocks == nil {
+		return -1 // interface method or external function
+	}
+	nodes, edges := 1, 0 // synthetic exit node
+	for _, b := range fn.Blocks {
+		if b == fn.Recover {
+			continue
+		}
+		nodes++
+		edges += len(b.Succs)
+		if len(b.Succs) == 0 {
+			edges++ // edge to exit node
+		}
+	}
+	return edges - nodes + 2
+}
+
+// ssaBlankImport lists the init functions of the package imported
+// by the selected blank import.
+func ssaBlankImport(o *Oracle, qpos *QueryPos, path []ast.Node) (queryResult, error) {
+	var spec *ast.ImportSpec
+	switch n := path[0].(type) {
+	case *ast.ImportSpec:
+		spec = n
+	case *ast.Ident:
+		spec, _ = path[1].(*ast.ImportSpec)
+	}
+	if spec == nil || spec.Name == nil || spec.Name.Name != "_" {
+		return nil, fmt.Errorf("ssa wants an expression or blank import; got %s",
+			astutil.NodeDescription(path[0]))
+	}
+	pkg := qpos.info.Defs[spec.Name].(*types.PkgName).Imported()
+
+	return &ssaInitsResult{
+		node:  spec,
+		pkg:   pkg,
+		inits: initFuncs(o, pkg),
+	}, nil
+}
+
+// initFuncs returns the positions of the init functions declared in
+// package pkg, in the order in which they are called by the package
+// initializer.
+func initFuncs(o *Oracle, pkg *types.Package) []token.Pos {
+	buildSSA(o)
+	p := o.prog.Package(pkg)
+	if p == nil {
+		return nil
+	}
+	var inits []token.Pos
+	for _, b := range p.Func("init").Blocks {
+		for _, instr := range b.Instrs {
+			if call, ok := instr.(*ssa.Call); ok {
+				// User-defined init functions are anonymous
+				// members named "init#1", "init#2", etc.
+				if fn := call.Call.StaticCallee(); fn != nil && fn.Pkg == p && strings.HasPrefix(fn.Name(), "init#") {
+					inits = append(inits, fn.Pos())
+				}
+			}
+		}
+	}
+	return inits
+}
+
+// domDepth returns the depth of block b in its dominator tree.
+func domDepth(b *ssa.BasicBlock) int {
+	depth := 0
+	for b = b.Idom(); b != nil; b = b.Idom() {
+		depth++
+	}
+	return depth
+}
+
+type ssaResult struct {
+	expr       ast.Expr        // selected expression
+	block      *ssa.BasicBlock // SSA block of the instruction computing expr, if any
+	local      *types.Var      // local variable denoted by expr, if any
+	captors    []*ssa.Function // closures capturing local
+	fn         *types.Func     // function denoted by expr, if any
+	complexity int             // cyclomatic complexity of fn, or -1 if unknown
+}
+
+func (r *ssaResult) display(printf printfFunc) {
+	desc := astutil.NodeDescription(r.expr)
+	b := r.block
+	switch {
+	case b == nil:
+		if r.local == nil && r.fn == nil {
+			printf(r.expr, "%s is not computed by an SSA instruction", desc)
+		}
+	case b.Idom() == nil:
+		printf(r.expr, "%s computed in block %d of %s, a root of the dominator tree",
+			desc, b.Index, b.Parent())
+	default:
+		printf(r.expr, "%s computed in block %d of %s, immediately dominated by block %d (depth %d)",
+			desc, b.Index, b.Parent(), b.Idom().Index, domDepth(b))
+	}
+
+	if v := r.local; v != nil {
+		if r.captors == nil {
+			printf(v, "%s is not captured by any closure", v.Name())
+		} else {
+			printf(v, "%s is captured by these %d closures:", v.Name(), len(r.captors))
+			for _, fn := range r.captors {
+				printf(fn, "\t%s", fn)
+			}
+		}
+	}
+
+	if r.complexity >= 0 {
+		printf(r.fn, "%s has cyclomatic complexity %d", r.fn.Name(), r.complexity)
+	}
+}
+
+func (r *ssaResult) toSerial(res *serial.Result, fset *token.FileSet) {
+	var block *serial.SSABlock
+	if b := r.block; b != nil {
+		block = &serial.SSABlock{
+			Func:  b.Parent().String(),
+			Index: b.Index,
+			Idom:  -1,
+			Depth: domDepth(b),
+		}
+		if idom := b.Idom(); idom != nil {
+			block.Idom = idom.Index
+		}
+	}
+	var captors []string
+	for _, fn := range r.captors {
+		captors = append(captors, fset.Position(fn.Pos()).String())
+	}
+	var complexity *int
+	if r.complexity >= 0 {
+		complexity = &r.complexity
+	}
+	res.SSA = &serial.SSA{
+		Desc:       astutil.NodeDescription(r.expr),
+		Pos:        fset.Position(r.expr.Pos()).String(),
+		Block:      block,
+		Captors:    captors,
+		Complexity: complexity,
+	}
+}
+
+type ssaInitsResult struct {
+	node  *ast.ImportSpec // selected blank import
+	pkg   *types.Package  // imported package
+	inits []token.Pos     // init functions of pkg
+}
+
+func (r *ssaInitsResult) display(printf printfFunc) {
+	if r.inits == nil {
+		printf(r.node, "Package %s has no init functions.", r.pkg.Name())
+	} else {
+		printf(r.node, "Package %s has these %d init functions:", r.pkg.Name(), len(r.inits))
+		for _, pos := range r.inits {
+			printf(pos, "\tfunc init")
+		}
+	}
+}
+
+func (r *ssaInitsResult) toSerial(res *serial.Result, fset *token.FileSet) {
+	var inits []string
+	for _, pos := range r.inits {
+		inits = append(inits, fset.Position(pos).String())
+	}
+	res.SSA = &serial.SSA{
+		Desc:  astutil.NodeDescription(r.node),
+		Pos:   fset.Position(r.node.Pos()).String(),
+		Inits: inits,
+	}
+}
@@ -0,0 +1,19 @@
+package blank
+
+// Tests of 'describe' and 'ssa' queries of blank imports.
+// See go.tools/oracle/oracle_test.go for explanation.
+// See blank.golden for expected query results.
+
+import _ "sideeffect" // @describe blank-import-name "_"
+
+import (
+	_ "lib" // @describe blank-import-spec "lib"
+)
+
+import (
+	_ "lib"        // @ssa blank-import-no-inits "_"
+	_ "sideeffect" // @ssa blank-import-inits "sideeffect"
+)
+
+func main() {
+}
@@ -0,0 +1,19 @@
+package main
+
+// Tests of 'ssa' queries for the closures capturing a local variable.
+// See go.tools/oracle/oracle_test.go for explanation.
+// See capture.golden for expected query results.
+
+func f(param int) func() int { // @ssa capture-param "param"
+	captured := 1   // @ssa capture-captured "captured"
+	uncaptured := 2 // @ssa capture-uncaptured "uncaptured"
+	_ = uncaptured
+	g := func() int {
+		return captured + func() int { return param * captured }()
+	}
+	return g
+}
+
+func main() {
+	f(0)()
+}
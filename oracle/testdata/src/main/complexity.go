@@ -0,0 +1,35 @@
+package main
+
+// Tests of 'ssa' queries for the cyclomatic complexity of a function.
+// See go.tools/oracle/oracle_test.go for explanation.
+// See complexity.golden for expected query results.
+
+func linear(x int) int { // @ssa complexity-linear "linear"
+	y := x * 2
+	return y + 1
+}
+
+func branchy(x int) int { // @ssa complexity-branchy "branchy"
+	if x < 0 {
+		return -x
+	}
+	for i := 0; i < x; i++ {
+		if i%2 == 0 {
+			x++
+		}
+	}
+	return x
+}
+
+func recovering() (err error) { // @ssa complexity-recovering "recovering"
+	defer func() {
+		recover()
+	}()
+	panic("oops")
+}
+
+func main() {
+	linear(1)
+	branchy(2)
+	recovering()
+}
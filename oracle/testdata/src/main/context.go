@@ -0,0 +1,24 @@
+package main
+
+// Tests of 'pointsto' queries for labels that differ only by the
+// context in which their allocation site was analyzed.
+// See go.tools/oracle/oracle_test.go for explanation.
+// See context.golden for expected query results.
+
+type T struct{}
+
+// newT is small enough to be analyzed anew for each call.
+func newT() *T { return new(T) }
+
+func main() {
+	p := newT()
+	q := newT()
+	r := p
+	if len("x") > 0 {
+		r = q
+	}
+	print(r) // @pointsto context-two "\\br\\b"
+
+	s := new(T)
+	print(s) // @pointsto context-one "s"
+}
@@ -0,0 +1,38 @@
+package convert
+
+// Tests of 'convert' queries.
+// See go.tools/oracle/oracle_test.go for explanation.
+// See convert.golden for expected query results.
+
+type celsius float64
+
+type stringer interface {
+	String() string
+}
+
+type name string
+
+func (n name) String() string { return string(n) }
+
+func main() {
+	var f float64
+	var c celsius
+	var n name
+	var p *int
+	var b []byte
+	const k = 1 << 10
+
+	_ = f   // @convert=float64 conv-same "f"
+	_ = f   // @convert=celsius conv-named "f"
+	_ = c   // @convert=float64 conv-underlying "c"
+	_ = n   // @convert=stringer conv-iface "n"
+	_ = b   // @convert=string conv-bytes "b"
+	_ = p   // @convert=*float64 conv-ptr "p"
+	_ = f   // @convert=func() conv-func "f"
+	_ = p   // @convert=unsafe.Pointer conv-undefined "p"
+	_ = 1   // @convert=celsius conv-const "1"
+	_ = 256 // @convert=uint8 conv-const-overflow "256"
+	_ = 65  // @convert=string conv-const-string "65"
+	_ = 0.5 // @convert=int conv-const-truncated "0.5"
+	_ = k   // @convert=int8 conv-const-ident "k"
+}
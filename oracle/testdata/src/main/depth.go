@@ -0,0 +1,21 @@
+package main
+
+// Tests of 'callstack' queries for the call depth of a function from
+// an entry point.
+// See go.tools/oracle/oracle_test.go for explanation.
+// See depth.golden for expected query results.
+
+func a() { b() }
+
+func b() { c() }
+
+func c() {} // @callstack depth-c "}"
+
+func d() { c() }
+
+func unused() {} // @callstack depth-unused "}"
+
+func main() { // @callstack depth-main "main"
+	a()
+	d()
+}
@@ -14,8 +14,21 @@ func main() { //
 		i = new(D)
 	}
 	print(i) // @describe desc-val-i "\\bi\\b"
+	i.f()    // @describe desc-val-imethod "f"
 
 	go main() // @describe desc-stmt "go"
+
+	const n, neg = 4, -1
+	var buf [n]byte
+	_ = buf[:n] // @describe desc-val-arraylen "\\bn\\b"
+	_ = neg     // @describe desc-val-arraylen-neg "neg"
+	_ = buf     // @describe desc-val-arraylen-nonconst "buf"
+
+	_ = main // @describe desc-val-func "main"
+
+	var x, y int
+	_ = x + y*len(buf)/int(n) // @describe desc-val-pure "x.*\\(n\\)"
+	_ = x + g(y)              // @describe desc-val-impure "x.*\\(y\\)"
 }
 
 type I interface {
@@ -25,5 +38,44 @@ type I interface {
 type C int // @describe desc-type-C "C"
 type D struct{}
 
-func (c C) f()  {}
+func g(int) int { return 0 }
+
+func (c C) f()  {} // @describe desc-val-method "\\bf\\b"
 func (d *D) f() {}
+
+type E struct { // @describe desc-type-E "E"
+	D
+	*C   `embedded:"ptr"`
+	x, y int `json:"xy"`
+	name string
+}
+
+type myint int // @describe desc-type-myint "myint"
+
+type Padded struct { // @describe desc-type-Padded "Padded"
+	hdr  [60]byte
+	hot  [8]byte // straddles the first cache line boundary
+	tail int32
+}
+
+func loop() {
+	for _ = range "ab" {
+		select {
+		default:
+			break // @describe desc-stmt-break "break"
+		}
+	}
+	print(2*3 + 1) // @describe desc-val-fold "2.3 . 1"
+	var _ [0]int   // @describe desc-type-zero "\\[0\\]int"
+
+	// File mimics *os.File, which implements io.Reader and io.Writer.
+	var f *File
+	var k int
+	print(f) // @describe desc-val-readwriter "f"
+	print(k) // @describe desc-val-int "k"
+}
+
+type File struct{ fd int }
+
+func (f *File) Read(p []byte) (n int, err error)  { return }
+func (f *File) Write(p []byte) (n int, err error) { return }
@@ -27,6 +27,22 @@ func main() { // @describe func-def-main "main"
 	_ = d.f    // @describe func-ref-d.f "d.f"
 	_ = i.f    // @describe func-ref-i.f "i.f"
 
+	// local interfaces with (and without) a method like D.f
+	type fer interface {
+		f()
+	}
+	type intfer interface {
+		f(int)
+	}
+	var _ fer = d
+
+	// method calls
+	var c C
+	d.f()    // @describe call-value-recv "d.f..."
+	c.f()    // @describe call-pointer-recv "c.f..."
+	(&c).f() // @describe call-pointer-recv-sel "f"
+	i.f()    // @describe call-interface "i.f..."
+
 	// var objects
 	anon := func() {
 		_ = d // @describe ref-lexical-d "d"
@@ -59,6 +75,8 @@ func main() { // @describe func-def-main "main"
 
 	print(1 + 2*3)        // @describe const-expr " 2.3"
 	print(real(1+2i) - 3) // @describe const-expr2 "real.*3"
+	print([]byte("abc"))  // @describe conv-bytes "..byte..abc.."
+	print(1<<3 + 2*(5-1)) // @describe const-fold "1<<3.*5-1."
 
 	m := map[string]*int{"a": &a}
 	mapval, _ := m["a"] // @describe map-lookup,ok "m..a.."
@@ -74,8 +92,69 @@ func main() { // @describe func-def-main "main"
 	_ = a2
 	var _ int // @describe var-decl-stmt2 "var _ int"
 	var _ int // @describe var-def-blank "_"
+
+	// chained method calls
+	var ch chainA
+	ch.b().c().d()          // @describe chain-call-b "ch.b..."
+	ch.b().c().d()          // @describe chain-call-c "ch.b...c..."
+	ch.b().c().d()          // @describe chain-call-d "ch.b...c...d..."
+	_ = ch.b().c().d() == 0 // @describe chain-ref-c "\\bc\\b"
+
+	// default targets of break and continue
+	for k := 0; k < 3; k++ {
+		switch k {
+		case 1:
+			break // @describe break-in-switch "break"
+		}
+		continue // @describe continue-in-for "continue"
+	}
+
+	// zero-size types
+	var zs struct{} // @describe type-zero-struct "struct{}"
+	var za [0]int   // @describe type-zero-array "\\[0\\]int"
+	var nz [1]int   // @describe type-nonzero-array "\\[1\\]int"
+	_, _, _ = zs, za, nz
+
+	// references to deprecated objects
+	oldFunc()         // @describe ref-deprecated-func "oldFunc"
+	var _ oldType     // @describe ref-deprecated-type "oldType"
+	_ = oldVar        // @describe ref-deprecated-var "oldVar"
+	_ = notDeprecated // @describe ref-not-deprecated "notDeprecated"
 }
 
+// Diamond embedding: f (via D) and g are ambiguous; h is promoted.
+type Amb struct { // @describe type-ambiguous "Amb"
+	E
+	F
+}
+type E struct{ D }
+type F struct{ D }
+
+func (E) g()  {}
+func (F) g()  {}
+func (*F) h() {}
+
+// Same-named local types in different functions.
+func loc1() {
+	type L int // @describe type-local-1 "L"
+}
+
+func (myerr) loc2() {
+	type L struct{} // @describe type-local-2 "L"
+}
+
+type myerr int // @describe type-error "myerr"
+
+func (myerr) Error() string { return "" }
+
+type chainA struct{}
+type chainB []int
+type chainC map[int]bool
+
+func (chainA) b() chainB  { return nil }
+func (chainB) c() chainC  { return nil }
+func (chainC) d() float64 { return 0 }
+
 type I interface { // @describe def-iface-I "I"
 	f() // @describe def-imethod-I.f "f"
 }
@@ -84,4 +163,61 @@ type C int
 type D struct{}
 
 func (c *C) f() {}
-func (d D) f()  {}
+func (d D) f()  {} // @describe def-method-D.f "\\bf\\b"
+
+type Padded struct { // @describe def-padded "Padded"
+	hdr  [60]byte
+	hot  [8]byte
+	tail int32
+}
+
+// oldFunc does nothing.
+//
+// Deprecated: call main instead; oldFunc
+// will be removed.
+func oldFunc() {}
+
+// Deprecated: use int.
+type oldType int
+
+var (
+	// Deprecated: use global.
+	oldVar int
+
+	// notDeprecated is not Deprecated: the notice
+	// must begin a paragraph.
+	notDeprecated int
+)
+
+var table [1 << 16]int64 // @describe var-def-table "table"
+
+var base = 1
+var derived = base * 2 // @describe var-def-derived "derived"
+
+func calls() {
+	defer oldFunc() // @describe call-defer "oldFunc\\(\\)"
+	go oldFunc()    // @describe call-go "oldFunc\\(\\)"
+	oldFunc()       // @describe call-plain "oldFunc\\(\\)"
+}
+
+func parse(s string) (int, error) { return 0, nil } // @describe def-func-err "parse"
+
+func length(s string) int { return len(s) } // @describe def-func-noerr "length"
+
+type Token int // @describe def-type-api "Token"
+
+type Lexer struct{}
+
+func Lex(s string) []Token { return nil }
+
+func (*Lexer) Next() (*Token, error) { return nil, nil }
+
+func (*Lexer) skip(t Token) {}
+
+func emit(t Token) {}
+
+const (
+	fits200  = 200     // @describe const-fits-200 "fits200"
+	fitsNeg  = -129    // @describe const-fits-neg "fitsNeg"
+	fitsHuge = 1 << 64 // @describe const-fits-huge "fitsHuge"
+)
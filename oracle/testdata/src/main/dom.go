@@ -0,0 +1,16 @@
+package main
+
+// Tests of 'ssa' queries.
+// See go.tools/oracle/oracle_test.go for explanation.
+// See dom.golden for expected query results.
+
+func f(x int) int {
+	if x > 0 { // @ssa ssa-cond "x > 0"
+		return x * 2 // @ssa ssa-then "x \\* 2"
+	}
+	return 0 // @ssa ssa-const "0"
+}
+
+func main() {
+	f(1) // @ssa ssa-call "f\\(1\\)"
+}
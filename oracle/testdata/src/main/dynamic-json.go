@@ -0,0 +1,24 @@
+package main
+
+// Tests of 'pointsto' queries for the number of concrete types an
+// interface value may hold, -format=json.
+// See go.tools/oracle/oracle_test.go for explanation.
+// See dynamic-json.golden for expected query results.
+
+type I interface {
+	f()
+}
+
+type C int
+type D struct{}
+
+func (C) f()  {}
+func (*D) f() {}
+
+func main() {
+	var i I = C(0)
+	if len("x") > 0 {
+		i = new(D)
+	}
+	i.f() // @pointsto dyn-json-two-types "i"
+}
@@ -0,0 +1,30 @@
+package main
+
+// Tests of 'pointsto' queries for the concrete types an interface
+// value may hold.
+// See go.tools/oracle/oracle_test.go for explanation.
+// See dynamic.golden for expected query results.
+
+type I interface {
+	f()
+}
+
+type C int
+type D struct{}
+
+func (C) f()  {}
+func (*D) f() {}
+
+func main() {
+	var i I = C(0)
+	if len("x") > 0 {
+		i = new(D)
+	}
+	i.f() // @pointsto dyn-two-types "i"
+
+	var j I = C(1)
+	j.f() // @pointsto dyn-one-type "j"
+
+	var n int
+	_ = n // @pointsto dyn-not-pointer "n"
+}
@@ -0,0 +1,41 @@
+package initorder
+
+// Tests of 'describe' queries of package-level variables:
+// initialization order and dependencies.
+// See go.tools/oracle/oracle_test.go for explanation.
+// See initorder.golden for expected query results.
+
+var a = b + c // @describe init-a "a"
+
+var b = f() // @describe init-b "b"
+
+var c = 1 // @describe init-c "c"
+
+var d = 2
+
+var z int // @describe init-z "z"
+
+var x, y = g() // @describe init-y "y"
+
+var t T
+
+var m = t.method() // @describe init-m "m"
+
+func f() int {
+	return d + z
+}
+
+func g() (int, int) {
+	return a, a
+}
+
+type T struct{}
+
+func (T) method() int {
+	return func() int { return c }()
+}
+
+func main() {
+	var local = a // @describe init-local "local"
+	_ = local
+}
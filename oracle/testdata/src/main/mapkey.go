@@ -0,0 +1,17 @@
+package main
+
+// Tests of 'mapkey' queries.
+// See go.tools/oracle/oracle_test.go for explanation.
+// See mapkey.golden for expected query results.
+
+type key struct{ a, b int } // @mapkey mapkey-key "key"
+
+type notKey struct{ a, b int } // @mapkey mapkey-notkey "notKey"
+
+type byKey map[key]notKey
+
+func main() {
+	m := make(map[key]bool)
+	_ = m
+	_ = byKey{}
+}
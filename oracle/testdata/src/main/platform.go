@@ -0,0 +1,21 @@
+package platform
+
+// Tests of target-dependent 'describe' results.
+// See TestDescribePlatform in go.tools/oracle/oracle_test.go.
+
+type T struct {
+	p *int
+	n int
+}
+
+// U's size and alignment depend on the maximum alignment.
+type U struct {
+	a int32
+	b int64
+}
+
+const big = 1 << 40 // too long for an array on 32-bit platforms
+
+var _ int64 = big
+
+func main() {}
@@ -14,8 +14,16 @@ func main() { //
 		i = new(D)
 	}
 	print(i) // @pointsto val-i "\\bi\\b"
+
+	q := &global
+	if q != nil {
+		q = new(int)
+	}
+	print(q) // @pointsto val-q "q"
 }
 
+var global int
+
 type I interface {
 	f()
 }
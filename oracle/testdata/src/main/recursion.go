@@ -0,0 +1,34 @@
+package main
+
+// Tests of 'recursion' queries.
+// See go.tools/oracle/oracle_test.go for explanation.
+// See recursion.golden for expected query results.
+
+func fact(n int) int { // @recursion recursion-direct "fact"
+	if n == 0 {
+		return 1
+	}
+	return n * fact(n-1)
+}
+
+func even(n int) bool { // @recursion recursion-even "even"
+	if n == 0 {
+		return true
+	}
+	return odd(n - 1)
+}
+
+func odd(n int) bool { // @recursion recursion-odd "odd"
+	if n == 0 {
+		return false
+	}
+	return even(n - 1)
+}
+
+func leaf() {} // @recursion recursion-none "leaf"
+
+func main() {
+	fact(3)
+	even(4)
+	leaf()
+}
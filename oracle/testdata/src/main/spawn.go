@@ -0,0 +1,25 @@
+package main
+
+// Tests of 'goroutines' queries.
+// See go.tools/oracle/oracle_test.go for explanation.
+// See spawn.golden for expected query results.
+
+func main() {
+	f()
+	idle()
+}
+
+func f() { // @goroutines goroutines-f "f"
+	g()
+}
+
+func g() {
+	go h()
+	go func() {}()
+}
+
+func h() { // @goroutines goroutines-h "h"
+	go idle()
+}
+
+func idle() {} // @goroutines goroutines-idle "idle"
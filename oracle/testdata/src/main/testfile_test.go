@@ -0,0 +1,8 @@
+package testfile
+
+// Tests of 'describe' queries for nodes in test files.
+// See TestDescribeTestFiles in go.tools/oracle/oracle_test.go.
+
+var T int
+
+func main() {}
@@ -0,0 +1,8 @@
+package testfile_test
+
+// Tests of 'describe' queries for nodes in external test packages.
+// See TestDescribeTestFiles in go.tools/oracle/oracle_test.go.
+
+var T int
+
+func main() {}
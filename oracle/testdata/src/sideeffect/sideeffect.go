@@ -0,0 +1,13 @@
+package sideeffect
+
+// A package imported only for the side effects of its init functions.
+
+var Var int
+
+func init() {
+	Var = 1
+}
+
+func init() {
+	Var++
+}
@@ -56,6 +56,8 @@ func what(posFlag string, buildContext *build.Context) (*Result, error) {
 		case *ast.FuncDecl:
 			enable["callers"] = true
 			enable["callstack"] = true
+			enable["goroutines"] = true
+			enable["recursion"] = true
 		case *ast.SendStmt:
 			enable["peers"] = true
 		case *ast.UnaryExpr:
@@ -98,6 +100,16 @@ func what(posFlag string, buildContext *build.Context) (*Result, error) {
 		}
 	}
 
+	// The mapkey mode applies to the same types as implements.
+	if on, ok := enable["implements"]; ok {
+		enable["mapkey"] = on
+	}
+
+	// The ssa mode applies to the same expressions as pointsto.
+	if on, ok := enable["pointsto"]; ok {
+		enable["ssa"] = on
+	}
+
 	// If we don't have an exact selection, disable modes that need one.
 	if !qpos.exact {
 		for _, minfo := range modes {